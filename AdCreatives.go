@@ -34,10 +34,10 @@ type AdCreative struct {
 
 type AdCreativeContent struct {
 	Reference string                      `json:"reference"`
-	TextAd    *AdCreativeContentTextAd    `json:"textAd"`
-	Jobs      *AdCreativeContentJobs      `json:"jobs"`
-	Spotlight *AdCreativeContentSpotlight `json:"spotlight"`
-	Follow    *AdCreativeContentFollow    `json:"follow"`
+	TextAd    *AdCreativeContentTextAd    `json:"textAd,omitempty"`
+	Jobs      *AdCreativeContentJobs      `json:"jobs,omitempty"`
+	Spotlight *AdCreativeContentSpotlight `json:"spotlight,omitempty"`
+	Follow    *AdCreativeContentFollow    `json:"follow,omitempty"`
 }
 
 type AdCreativeContentTextAd struct {
@@ -218,3 +218,37 @@ func (service *Service) SearchAdCreatives(config *SearchAdCreativesConfig) (*[]A
 
 	return &adCreatives, nil
 }
+
+// CreateAdCreative creates a creative in the given account and returns its urn
+func (service *Service) CreateAdCreative(accountId int64, adCreative *AdCreative) (string, *errortools.Error) {
+	if service == nil {
+		return "", errortools.ErrorMessage("Service pointer is nil")
+	}
+	if adCreative == nil {
+		return "", errortools.ErrorMessage("AdCreative pointer is nil")
+	}
+
+	var header = http.Header{}
+	header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+
+	requestConfig := go_http.RequestConfig{
+		Method:            http.MethodPost,
+		Url:               service.urlRest(fmt.Sprintf("adAccounts/%v/creatives", accountId)),
+		BodyModel:         adCreative,
+		NonDefaultHeaders: &header,
+	}
+	_, resp, e := service.versionedHttpRequest(&requestConfig, nil)
+	if e != nil {
+		return "", e
+	}
+
+	var creativeUrn = resp.Header.Get("X-Restli-Id")
+	if creativeUrn == "" {
+		creativeUrn = resp.Header.Get("X-Linkedin-Id")
+	}
+	if creativeUrn == "" {
+		return "", errortools.ErrorMessage("CreateAdCreative did not return creative urn in header")
+	}
+
+	return creativeUrn, nil
+}
@@ -1,10 +1,14 @@
 package linkedin
 
 import (
+	"bytes"
+	"fmt"
 	errortools "github.com/leapforce-libraries/go_errortools"
 	go_http "github.com/leapforce-libraries/go_http"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 )
 
 type RegisterUploadAssetRecipe string
@@ -40,18 +44,8 @@ type RegisterUploadAssetResponse struct {
 				} `json:"headers"`
 			} `json:"com.linkedin.digitalmedia.uploading.MediaUploadHttpRequest"`
 			MultipartUpload *struct {
-				PartUploadRequests []struct {
-					Headers struct {
-						ContentType string `json:"Content-Type"`
-					} `json:"headers"`
-					ByteRange struct {
-						LastByte  int `json:"lastByte"`
-						FirstByte int `json:"firstByte"`
-					} `json:"byteRange"`
-					Url          string `json:"url"`
-					UrlExpiresAt int64  `json:"urlExpiresAt"`
-				} `json:"partUploadRequests"`
-				Metadata string `json:"metadata"`
+				PartUploadRequests []PartUploadRequest `json:"partUploadRequests"`
+				Metadata           string              `json:"metadata"`
 			} `json:"com.linkedin.digitalmedia.uploading.MultipartUpload"`
 		} `json:"uploadMechanism"`
 		Asset         string `json:"asset"`
@@ -59,6 +53,18 @@ type RegisterUploadAssetResponse struct {
 	} `json:"value"`
 }
 
+type PartUploadRequest struct {
+	Headers struct {
+		ContentType string `json:"Content-Type"`
+	} `json:"headers"`
+	ByteRange struct {
+		LastByte  int `json:"lastByte"`
+		FirstByte int `json:"firstByte"`
+	} `json:"byteRange"`
+	Url          string `json:"url"`
+	UrlExpiresAt int64  `json:"urlExpiresAt"`
+}
+
 type InitializeUploadAssetInstruction struct {
 	UploadUrl string `json:"uploadUrl"`
 	FirstByte int64  `json:"firstByte"`
@@ -80,8 +86,7 @@ func (service *Service) RegisterUploadAsset(req *RegisterUploadAssetRequest) (*R
 	var registerUploadAssetResponse RegisterUploadAssetResponse
 
 	var header = http.Header{}
-	header.Set("X-Restli-Protocol-Version", "2.0.0")
-	header.Set("LinkedIn-Version", "202209")
+	header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
 
 	requestConfig := go_http.RequestConfig{
 		Method:            http.MethodPost,
@@ -97,37 +102,79 @@ func (service *Service) RegisterUploadAsset(req *RegisterUploadAssetRequest) (*R
 	return &registerUploadAssetResponse, nil
 }
 
+// UploadAsset downloads the file at url and uploads it to the uploadUrl returned by RegisterUploadAsset, it returns the etag of the upload.
+// The file is streamed if the server sends its Content-Length, otherwise it is read into memory first.
 func (service *Service) UploadAsset(putUrl string, url string) (string, *errortools.Error) {
 	if service == nil {
 		return "", errortools.ErrorMessage("Service pointer is nil")
 	}
 
-	resp, err := http.Get(url)
-	if err != nil {
-		return "", errortools.ErrorMessage(err)
+	body, size, e := openMediaUrl(url, MaxAssetSize)
+	if e != nil {
+		return "", e
 	}
 
-	defer resp.Body.Close()
+	defer body.Close()
 
-	bytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", errortools.ErrorMessage(err)
+	if size < 0 {
+		b, e := readMedia(body, -1, MaxAssetSize)
+		if e != nil {
+			return "", e
+		}
+		return service.uploadAsset(putUrl, bytes.NewReader(b), int64(len(b)))
 	}
 
-	var header = http.Header{}
-	header.Set("Content-Type", http.DetectContentType(bytes))
+	return service.uploadAsset(putUrl, body, size)
+}
 
-	requestConfig := go_http.RequestConfig{
-		Method:            http.MethodPut,
-		Url:               putUrl,
-		BodyRaw:           &bytes,
-		NonDefaultHeaders: &header,
+// UploadAssetFromReader streams size bytes read from reader to the uploadUrl returned by RegisterUploadAsset
+func (service *Service) UploadAssetFromReader(putUrl string, reader io.Reader, size int64) (string, *errortools.Error) {
+	if service == nil {
+		return "", errortools.ErrorMessage("Service pointer is nil")
+	}
+	if reader == nil {
+		return "", errortools.ErrorMessage("Reader is nil")
 	}
-	_, resp, e := service.versionedHttpRequest(&requestConfig, nil)
 
-	etag := resp.Header.Get("etag")
+	return service.uploadAsset(putUrl, reader, size)
+}
+
+// UploadAssetFromFile streams the file at path to the uploadUrl returned by RegisterUploadAsset
+func (service *Service) UploadAssetFromFile(putUrl string, path string) (string, *errortools.Error) {
+	if service == nil {
+		return "", errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	file, size, e := openMedia(path)
+	if e != nil {
+		return "", e
+	}
+
+	defer file.Close()
 
-	return etag, e
+	return service.uploadAsset(putUrl, file, size)
+}
+
+// uploadAsset checks the format of the asset, JPEG, PNG, GIF or MP4, and streams it to putUrl
+func (service *Service) uploadAsset(putUrl string, reader io.Reader, size int64) (string, *errortools.Error) {
+	if size <= 0 {
+		return "", errortools.ErrorMessage("Size of the asset must be known and positive")
+	}
+	if size > MaxAssetSize {
+		return "", errortools.ErrorMessagef("Size of %v bytes exceeds the maximum size of %v bytes", size, MaxAssetSize)
+	}
+
+	contentType, reader, e := sniffMedia(reader, supportedAssetContentTypes)
+	if e != nil {
+		return "", errortools.ErrorMessagef("%s, supported are JPEG, PNG, GIF and MP4", e.Message())
+	}
+
+	resp, e := service.streamMedia(putUrl, reader, size, contentType)
+	if e != nil {
+		return "", e
+	}
+
+	return resp.Header.Get("etag"), nil
 }
 
 type CompleteMultipartUploadAssetRequest struct {
@@ -146,17 +193,179 @@ func (service *Service) CompleteMultipartUploadAsset(completeMultipartUploadAsse
 	if service == nil {
 		return errortools.ErrorMessage("Service pointer is nil")
 	}
+	if completeMultipartUploadAssetRequest == nil {
+		return errortools.ErrorMessage("CompleteMultipartUploadAssetRequest pointer is nil")
+	}
 
 	var completeMultipartUploadAssetRequest_ = struct {
 		CompleteMultipartUploadAssetRequest `json:"completeMultipartUploadRequest"`
 	}{*completeMultipartUploadAssetRequest}
 
+	var header = http.Header{}
+	header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+
 	requestConfig := go_http.RequestConfig{
-		Method:    http.MethodPost,
-		Url:       service.urlRest("assets?action=completeMultiPartUpload"),
-		BodyModel: completeMultipartUploadAssetRequest_,
+		Method:            http.MethodPost,
+		Url:               service.urlRest("assets?action=completeMultiPartUpload"),
+		BodyModel:         completeMultipartUploadAssetRequest_,
+		NonDefaultHeaders: &header,
 	}
 	_, _, e := service.versionedHttpRequest(&requestConfig, nil)
 
 	return e
 }
+
+// UploadAssetMultipart uploads the byte ranges of a multipart upload registered with RegisterUploadAsset,
+// read from reader, and completes the upload
+func (service *Service) UploadAssetMultipart(registerUploadAssetResponse *RegisterUploadAssetResponse, reader io.ReaderAt) *errortools.Error {
+	if service == nil {
+		return errortools.ErrorMessage("Service pointer is nil")
+	}
+	if registerUploadAssetResponse == nil {
+		return errortools.ErrorMessage("RegisterUploadAssetResponse pointer is nil")
+	}
+	if reader == nil {
+		return errortools.ErrorMessage("Reader must not be nil")
+	}
+
+	multipartUpload := registerUploadAssetResponse.Value.UploadMechanism.MultipartUpload
+	if multipartUpload == nil {
+		return errortools.ErrorMessage("Upload is not a multipart upload, register it with a FileSize")
+	}
+
+	completeMultipartUploadAssetRequest := CompleteMultipartUploadAssetRequest{
+		MediaArtifact: registerUploadAssetResponse.Value.MediaArtifact,
+		Metadata:      multipartUpload.Metadata,
+	}
+
+	for _, partUploadRequest := range multipartUpload.PartUploadRequests {
+		partUploadResponse, e := service.uploadAssetPart(&partUploadRequest, reader)
+		if e != nil {
+			return e
+		}
+
+		completeMultipartUploadAssetRequest.PartUploadResponses = append(completeMultipartUploadAssetRequest.PartUploadResponses, *partUploadResponse)
+	}
+
+	return service.CompleteMultipartUploadAsset(&completeMultipartUploadAssetRequest)
+}
+
+func (service *Service) uploadAssetPart(partUploadRequest *PartUploadRequest, reader io.ReaderAt) (*PartUploadResponse, *errortools.Error) {
+	firstByte := int64(partUploadRequest.ByteRange.FirstByte)
+	lastByte := int64(partUploadRequest.ByteRange.LastByte)
+	if lastByte < firstByte {
+		return nil, errortools.ErrorMessagef("Invalid byte range %v-%v", firstByte, lastByte)
+	}
+
+	b := make([]byte, lastByte-firstByte+1)
+	n, err := reader.ReadAt(b, firstByte)
+	if n < len(b) {
+		if err == nil || err == io.EOF {
+			return nil, errortools.ErrorMessagef("Read %v bytes at offset %v, expected %v", n, firstByte, len(b))
+		}
+		return nil, errortools.ErrorMessage(err)
+	}
+
+	var header = http.Header{}
+	contentType := partUploadRequest.Headers.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header.Set("Content-Type", contentType)
+
+	requestConfig := go_http.RequestConfig{
+		Method:            http.MethodPut,
+		Url:               partUploadRequest.Url,
+		BodyRaw:           &b,
+		NonDefaultHeaders: &header,
+	}
+	_, resp, e := service.oAuth2Service.HttpRequest(&requestConfig)
+	if e != nil {
+		return nil, e
+	}
+
+	etag := resp.Header.Get("etag")
+	if etag == "" {
+		return nil, errortools.ErrorMessage("UploadAssetMultipart did not return etag header")
+	}
+
+	var partUploadResponse PartUploadResponse
+	partUploadResponse.Headers.ETag = etag
+	partUploadResponse.HttpStatusCode = resp.StatusCode
+
+	return &partUploadResponse, nil
+}
+
+type AssetStatus string
+
+const (
+	AssetStatusWaitingUpload AssetStatus = "WAITING_UPLOAD"
+	AssetStatusProcessing    AssetStatus = "PROCESSING"
+	AssetStatusAvailable     AssetStatus = "AVAILABLE"
+	AssetStatusClientError   AssetStatus = "CLIENT_ERROR"
+	AssetStatusServerError   AssetStatus = "SERVER_ERROR"
+)
+
+type Asset struct {
+	Id              string        `json:"id"`
+	Status          string        `json:"status"`
+	MediaTypeFamily string        `json:"mediaTypeFamily"`
+	Recipes         []AssetRecipe `json:"recipes"`
+}
+
+type AssetRecipe struct {
+	Recipe RegisterUploadAssetRecipe `json:"recipe"`
+	Status AssetStatus               `json:"status"`
+}
+
+// RecipeStatus returns the status of recipe, empty if the asset was not registered for it
+func (asset *Asset) RecipeStatus(recipe RegisterUploadAssetRecipe) AssetStatus {
+	for _, assetRecipe := range asset.Recipes {
+		if assetRecipe.Recipe == recipe {
+			return assetRecipe.Status
+		}
+	}
+
+	return ""
+}
+
+// Available returns whether all recipes of the asset are available
+func (asset *Asset) Available() bool {
+	if len(asset.Recipes) == 0 {
+		return false
+	}
+	for _, assetRecipe := range asset.Recipes {
+		if assetRecipe.Status != AssetStatusAvailable {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetAsset returns the asset with its recipe statuses, assetId being the id or the urn:li:digitalmediaAsset urn
+func (service *Service) GetAsset(assetId string) (*Asset, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	assetId = strings.TrimPrefix(assetId, DigitalmediaAssetUrnPrefix)
+
+	var header = http.Header{}
+	header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+
+	var asset Asset
+
+	requestConfig := go_http.RequestConfig{
+		Method:            http.MethodGet,
+		Url:               service.urlRest(fmt.Sprintf("assets/%s", url.PathEscape(assetId))),
+		ResponseModel:     &asset,
+		NonDefaultHeaders: &header,
+	}
+	_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+	if e != nil {
+		return nil, e
+	}
+
+	return &asset, nil
+}
@@ -1,11 +1,12 @@
 package linkedin
 
 import (
-	"encoding/json"
 	"fmt"
 	errortools "github.com/leapforce-libraries/go_errortools"
 	go_http "github.com/leapforce-libraries/go_http"
 	"net/http"
+	"net/url"
+	"strings"
 )
 
 type CommentsResponse struct {
@@ -40,19 +41,81 @@ type CommentMessage struct {
 }
 
 type CommentAttribute struct {
-	Length int64           `json:"length"`
-	Start  int64           `json:"start"`
-	Value  json.RawMessage `json:"value"`
+	Length int64                 `json:"length"`
+	Start  int64                 `json:"start"`
+	Value  CommentAttributeValue `json:"value"`
 }
 
+// CommentAttributeValue holds exactly one of a member mention, organization mention or hashtag
+type CommentAttributeValue struct {
+	Member  *CommentAttributeMember  `json:"com.linkedin.common.MemberAttributedEntity,omitempty"`
+	Company *CommentAttributeCompany `json:"com.linkedin.common.CompanyAttributedEntity,omitempty"`
+	Hashtag *CommentAttributeHashtag `json:"com.linkedin.common.HashtagAttributedEntity,omitempty"`
+}
+
+type CommentAttributeMember struct {
+	Member string `json:"member"`
+}
+
+type CommentAttributeCompany struct {
+	Company string `json:"company"`
+}
+
+type CommentAttributeHashtag struct {
+	Hashtag string `json:"hashtag"`
+}
+
+// NewMentionAttribute returns the attribute for a mention of a member (urn:li:person) or organization (urn:li:organization)
+// starting at start with the given length in the message text
+func NewMentionAttribute(urn string, start int64, length int64) CommentAttribute {
+	var value CommentAttributeValue
+	if strings.HasPrefix(urn, OrganizationUrnPrefix) {
+		value.Company = &CommentAttributeCompany{Company: urn}
+	} else {
+		value.Member = &CommentAttributeMember{Member: urn}
+	}
+
+	return CommentAttribute{
+		Length: length,
+		Start:  start,
+		Value:  value,
+	}
+}
+
+func NewHashtagAttribute(hashtag string, start int64, length int64) CommentAttribute {
+	return CommentAttribute{
+		Length: length,
+		Start:  start,
+		Value: CommentAttributeValue{
+			Hashtag: &CommentAttributeHashtag{Hashtag: strings.TrimPrefix(hashtag, "#")},
+		},
+	}
+}
+
+const (
+	CommentContentTypeImage string = "IMAGE"
+)
+
 type CommentContent struct {
 	Type   string               `json:"type"`
 	Entity CommentContentEntity `json:"entity"`
-	Url    string               `json:"url"`
+	Url    string               `json:"url,omitempty"`
 }
 
 type CommentContentEntity struct {
-	DigitalmediaAsset string `json:"digitalmediaAsset"`
+	DigitalmediaAsset string `json:"digitalmediaAsset,omitempty"`
+	Image             string `json:"image,omitempty"`
+}
+
+// socialActionsUrl returns the comments url of a post or comment, urn being a urn:li:share, urn:li:ugcPost or urn:li:comment,
+// either plain or already url encoded
+func (service *Service) socialActionsUrl(urn string, path string) string {
+	// urns never contain '%', so decoding first prevents encoding an encoded urn twice
+	if unescaped, err := url.PathUnescape(urn); err == nil {
+		urn = unescaped
+	}
+
+	return service.urlRest(fmt.Sprintf("socialActions/%s/comments%s", url.QueryEscape(urn), path))
 }
 
 func (service *Service) GetComments(urn string) (*[]Comment, *errortools.Error) {
@@ -62,7 +125,7 @@ func (service *Service) GetComments(urn string) (*[]Comment, *errortools.Error)
 
 	comments := []Comment{}
 
-	url := service.urlRest(fmt.Sprintf("socialActions/%s/comments", urn))
+	url := service.socialActionsUrl(urn, "")
 
 	for {
 		commentsResponse := CommentsResponse{}
@@ -106,7 +169,7 @@ func (service *Service) CreateComment(urn string, comment *Comment) (*Comment, *
 
 	var newComment Comment
 
-	url := service.urlRest(fmt.Sprintf("socialActions/%s/comments", urn))
+	url := service.socialActionsUrl(urn, "")
 
 	requestConfig := go_http.RequestConfig{
 		Method:        http.MethodPost,
@@ -121,3 +184,121 @@ func (service *Service) CreateComment(urn string, comment *Comment) (*Comment, *
 
 	return &newComment, resp, nil
 }
+
+// GetComment returns a single comment on a post or comment
+func (service *Service) GetComment(urn string, commentId string) (*Comment, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	var comment Comment
+
+	requestConfig := go_http.RequestConfig{
+		Method:        http.MethodGet,
+		Url:           service.socialActionsUrl(urn, fmt.Sprintf("/%s", url.PathEscape(commentId))),
+		ResponseModel: &comment,
+	}
+	_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+	if e != nil {
+		return nil, e
+	}
+
+	return &comment, nil
+}
+
+// GetCommentReplies returns the replies to a comment, commentUrn being the $URN of the parent comment
+func (service *Service) GetCommentReplies(commentUrn string) (*[]Comment, *errortools.Error) {
+	return service.GetComments(commentUrn)
+}
+
+// UpdateComment replaces the message of a comment, actor must be the author of the comment
+func (service *Service) UpdateComment(urn string, commentId string, actor string, message *CommentMessage) *errortools.Error {
+	if service == nil {
+		return errortools.ErrorMessage("Service pointer is nil")
+	}
+	if message == nil {
+		return errortools.ErrorMessage("CommentMessage pointer is nil")
+	}
+
+	var header = http.Header{}
+	header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+	header.Set("X-RestLi-Method", "PARTIAL_UPDATE")
+
+	var set = map[string]interface{}{
+		"text": message.Text,
+	}
+	if message.Attributes != nil {
+		set["attributes"] = *message.Attributes
+	}
+
+	requestConfig := go_http.RequestConfig{
+		Method: http.MethodPost,
+		Url:    service.socialActionsUrl(urn, fmt.Sprintf("/%s?actor=%s", url.PathEscape(commentId), url.QueryEscape(actor))),
+		BodyModel: map[string]interface{}{
+			"patch": map[string]interface{}{
+				"message": map[string]interface{}{
+					"$set": set,
+				},
+			},
+		},
+		NonDefaultHeaders: &header,
+	}
+	_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+
+	return e
+}
+
+// DeleteComment deletes a comment, actor being the author of the comment or the organization that owns the post
+func (service *Service) DeleteComment(urn string, commentId string, actor string) *errortools.Error {
+	if service == nil {
+		return errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	var header = http.Header{}
+	header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+	header.Set("X-RestLi-Method", "DELETE")
+
+	requestConfig := go_http.RequestConfig{
+		Method:            http.MethodDelete,
+		Url:               service.socialActionsUrl(urn, fmt.Sprintf("/%s?actor=%s", url.PathEscape(commentId), url.QueryEscape(actor))),
+		NonDefaultHeaders: &header,
+	}
+	_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+
+	return e
+}
+
+// CreateImageComment uploads the image at imageUrl on behalf of the comment's actor and creates the comment with the image as content
+func (service *Service) CreateImageComment(urn string, comment *Comment, imageUrl string) (*Comment, *http.Response, *errortools.Error) {
+	if service == nil {
+		return nil, nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+	if comment == nil {
+		return nil, nil, errortools.ErrorMessage("Comment pointer is nil")
+	}
+	if comment.Actor == nil {
+		return nil, nil, errortools.ErrorMessage("Comment actor is required")
+	}
+
+	initializeUploadImageResponse, e := service.InitializeUploadImage(*comment.Actor)
+	if e != nil {
+		return nil, nil, e
+	}
+
+	e = service.UploadImage(initializeUploadImageResponse.Value.UploadUrl, imageUrl)
+	if e != nil {
+		return nil, nil, e
+	}
+
+	var comment_ = *comment
+	comment_.Content = &[]CommentContent{
+		{
+			Type: CommentContentTypeImage,
+			Entity: CommentContentEntity{
+				Image: initializeUploadImageResponse.Value.Image,
+			},
+		},
+	}
+
+	return service.CreateComment(urn, &comment_)
+}
@@ -0,0 +1,326 @@
+package linkedin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	errortools "github.com/leapforce-libraries/go_errortools"
+	go_http "github.com/leapforce-libraries/go_http"
+)
+
+const (
+	maxConversionEventsPerCall int           = 5000
+	maxConversionEventAge      time.Duration = 90 * 24 * time.Hour
+	maxConversionEventIdLength int           = 256
+)
+
+type ConversionUserIdType string
+
+const (
+	ConversionUserIdTypeSha256Email                       ConversionUserIdType = "SHA256_EMAIL"
+	ConversionUserIdTypeLinkedInFirstPartyAdsTrackingUuid ConversionUserIdType = "LINKEDIN_FIRST_PARTY_ADS_TRACKING_UUID"
+	ConversionUserIdTypeAcxiomId                          ConversionUserIdType = "ACXIOM_ID"
+	ConversionUserIdTypeOracleMoatId                      ConversionUserIdType = "ORACLE_MOAT_ID"
+)
+
+type ConversionEvent struct {
+	Conversion           string               `json:"conversion"`
+	ConversionHappenedAt int64                `json:"conversionHappenedAt"`
+	ConversionValue      *ConversionValue     `json:"conversionValue,omitempty"`
+	User                 *ConversionEventUser `json:"user"`
+	// EventId deduplicates the event with the same event sent by the Insight Tag
+	EventId string `json:"eventId,omitempty"`
+}
+
+type ConversionEventUser struct {
+	UserIds  []ConversionUserId  `json:"userIds"`
+	UserInfo *ConversionUserInfo `json:"userInfo,omitempty"`
+}
+
+type ConversionUserId struct {
+	IdType  ConversionUserIdType `json:"idType"`
+	IdValue string               `json:"idValue"`
+}
+
+type ConversionUserInfo struct {
+	FirstName   string `json:"firstName"`
+	LastName    string `json:"lastName"`
+	CompanyName string `json:"companyName,omitempty"`
+	Title       string `json:"title,omitempty"`
+	CountryCode string `json:"countryCode,omitempty"`
+}
+
+var sha256HexRegexp = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// ConversionUserBuilder builds the user of a conversion event, personal data is hashed locally before it is sent
+type ConversionUserBuilder struct {
+	user ConversionEventUser
+	err  *errortools.Error
+}
+
+func NewConversionUserBuilder() *ConversionUserBuilder {
+	return &ConversionUserBuilder{}
+}
+
+func (builder *ConversionUserBuilder) addUserId(idType ConversionUserIdType, idValue string) *ConversionUserBuilder {
+	if idValue == "" {
+		return builder
+	}
+	for _, userId := range builder.user.UserIds {
+		if userId.IdType == idType {
+			if builder.err == nil {
+				builder.err = errortools.ErrorMessagef("User id of type %s is set more than once", idType)
+			}
+			return builder
+		}
+	}
+
+	builder.user.UserIds = append(builder.user.UserIds, ConversionUserId{IdType: idType, IdValue: idValue})
+
+	return builder
+}
+
+// NormalizeEmail trims and lowercases an email address as required before hashing
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// HashEmail returns the hex encoded SHA-256 hash of the normalized email address
+func HashEmail(email string) string {
+	hash := sha256.Sum256([]byte(NormalizeEmail(email)))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// Email normalizes and hashes a plain text email address
+func (builder *ConversionUserBuilder) Email(email string) *ConversionUserBuilder {
+	email = NormalizeEmail(email)
+	if email == "" {
+		return builder
+	}
+	if !strings.Contains(email, "@") {
+		if builder.err == nil {
+			// the value is not included, it is personal data
+			builder.err = errortools.ErrorMessage("Email is not a valid email address")
+		}
+		return builder
+	}
+
+	return builder.addUserId(ConversionUserIdTypeSha256Email, HashEmail(email))
+}
+
+// HashedEmail sets an email address that was already normalized and hashed with SHA-256
+func (builder *ConversionUserBuilder) HashedEmail(hash string) *ConversionUserBuilder {
+	hash = strings.ToLower(strings.TrimSpace(hash))
+	if hash != "" && !sha256HexRegexp.MatchString(hash) {
+		if builder.err == nil {
+			builder.err = errortools.ErrorMessage("Hashed email must be a hex encoded SHA-256 hash")
+		}
+		return builder
+	}
+
+	return builder.addUserId(ConversionUserIdTypeSha256Email, hash)
+}
+
+// LinkedInFirstPartyAdsTrackingUuid sets the value of the li_fat_id click id or first-party cookie
+func (builder *ConversionUserBuilder) LinkedInFirstPartyAdsTrackingUuid(uuid string) *ConversionUserBuilder {
+	return builder.addUserId(ConversionUserIdTypeLinkedInFirstPartyAdsTrackingUuid, strings.TrimSpace(uuid))
+}
+
+func (builder *ConversionUserBuilder) AcxiomId(acxiomId string) *ConversionUserBuilder {
+	return builder.addUserId(ConversionUserIdTypeAcxiomId, strings.TrimSpace(acxiomId))
+}
+
+func (builder *ConversionUserBuilder) OracleMoatId(oracleMoatId string) *ConversionUserBuilder {
+	return builder.addUserId(ConversionUserIdTypeOracleMoatId, strings.TrimSpace(oracleMoatId))
+}
+
+// UserInfo sets name, company, title and country, used for matching when the user ids do not match
+func (builder *ConversionUserBuilder) UserInfo(userInfo ConversionUserInfo) *ConversionUserBuilder {
+	builder.user.UserInfo = &userInfo
+
+	return builder
+}
+
+func (builder *ConversionUserBuilder) Build() (*ConversionEventUser, *errortools.Error) {
+	if builder.err != nil {
+		return nil, builder.err
+	}
+
+	if builder.user.UserInfo != nil {
+		if builder.user.UserInfo.FirstName == "" || builder.user.UserInfo.LastName == "" {
+			return nil, errortools.ErrorMessage("UserInfo requires firstName and lastName")
+		}
+	}
+	if len(builder.user.UserIds) == 0 {
+		return nil, errortools.ErrorMessage("At least one user id is required")
+	}
+
+	user := builder.user
+	user.UserIds = append([]ConversionUserId{}, builder.user.UserIds...)
+
+	return &user, nil
+}
+
+func (event *ConversionEvent) Validate() *errortools.Error {
+	if !strings.HasPrefix(event.Conversion, ConversionUrnPrefix) {
+		return errortools.ErrorMessagef("Conversion '%s' is not a %s urn", event.Conversion, ConversionUrnPrefix)
+	}
+	if event.ConversionHappenedAt <= 0 {
+		return errortools.ErrorMessage("ConversionHappenedAt is required")
+	}
+	if time.Since(time.UnixMilli(event.ConversionHappenedAt)) > maxConversionEventAge {
+		return errortools.ErrorMessage("ConversionHappenedAt must be within the last 90 days")
+	}
+	if event.User == nil || len(event.User.UserIds) == 0 {
+		return errortools.ErrorMessage("User with at least one user id is required")
+	}
+	if len(event.EventId) > maxConversionEventIdLength {
+		return errortools.ErrorMessagef("EventId must not exceed %v characters", maxConversionEventIdLength)
+	}
+
+	return nil
+}
+
+// SendConversionEvent streams a single server-side conversion event
+func (service *Service) SendConversionEvent(event *ConversionEvent) *errortools.Error {
+	if service == nil {
+		return errortools.ErrorMessage("Service pointer is nil")
+	}
+	if event == nil {
+		return errortools.ErrorMessage("ConversionEvent pointer is nil")
+	}
+
+	e := event.Validate()
+	if e != nil {
+		return e
+	}
+
+	var header = http.Header{}
+	header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+
+	requestConfig := go_http.RequestConfig{
+		Method:            http.MethodPost,
+		Url:               service.urlRest("conversionEvents"),
+		BodyModel:         event,
+		NonDefaultHeaders: &header,
+	}
+	_, _, e = service.versionedHttpRequest(&requestConfig, nil)
+
+	return e
+}
+
+type ConversionEventsResponse struct {
+	Elements []struct {
+		Status int `json:"status"`
+		Error  *struct {
+			Message string `json:"message"`
+			Status  int    `json:"status"`
+		} `json:"error,omitempty"`
+	} `json:"elements"`
+}
+
+// ConversionEventResult holds the outcome of one event sent by SendConversionEvents
+type ConversionEventResult struct {
+	Index   int // index of the event in the slice passed to SendConversionEvents
+	EventId string
+	Status  int
+	Error   string
+}
+
+func (result *ConversionEventResult) Succeeded() bool {
+	return result.Error == "" && result.Status == http.StatusCreated
+}
+
+// SendConversionEvents streams conversion events in batches of at most 5000 and returns the result per event,
+// events failing local validation are not sent and get status 0.
+// When a batch fails the results are returned along with the error, events of the failed and remaining batches have their Error set.
+func (service *Service) SendConversionEvents(events []ConversionEvent) (*[]ConversionEventResult, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	results := make([]ConversionEventResult, len(events))
+
+	var valid []int
+	for i := range events {
+		results[i] = ConversionEventResult{
+			Index:   i,
+			EventId: events[i].EventId,
+		}
+
+		e := events[i].Validate()
+		if e != nil {
+			results[i].Error = e.Message()
+			continue
+		}
+		valid = append(valid, i)
+	}
+
+	for len(valid) > 0 {
+		var batch []int
+
+		if len(valid) > maxConversionEventsPerCall {
+			batch = valid[:maxConversionEventsPerCall]
+			valid = valid[maxConversionEventsPerCall:]
+		} else {
+			batch = valid
+			valid = []int{}
+		}
+
+		var elements []ConversionEvent
+		for _, i := range batch {
+			elements = append(elements, events[i])
+		}
+
+		var header = http.Header{}
+		header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+		header.Set("X-RestLi-Method", "BATCH_CREATE")
+
+		var conversionEventsResponse ConversionEventsResponse
+
+		requestConfig := go_http.RequestConfig{
+			Method: http.MethodPost,
+			Url:    service.urlRest("conversionEvents"),
+			BodyModel: struct {
+				Elements []ConversionEvent `json:"elements"`
+			}{elements},
+			ResponseModel:     &conversionEventsResponse,
+			NonDefaultHeaders: &header,
+		}
+		_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+		if e == nil && len(conversionEventsResponse.Elements) != len(batch) {
+			e = errortools.ErrorMessagef("Received %v results for %v conversion events", len(conversionEventsResponse.Elements), len(batch))
+		}
+		if e != nil {
+			// the events of the failed batch may or may not have been recorded, the remaining batches were not sent
+			for _, i := range batch {
+				results[i].Error = fmt.Sprintf("Conversion event batch failed: %s", e.Message())
+			}
+			for _, i := range valid {
+				results[i].Error = "Conversion event not sent: a previous batch failed"
+			}
+			return &results, e
+		}
+
+		for j, element := range conversionEventsResponse.Elements {
+			result := &results[batch[j]]
+			result.Status = element.Status
+			if element.Error != nil {
+				result.Error = element.Error.Message
+				if result.Status == 0 {
+					result.Status = element.Error.Status
+				}
+			} else if element.Status >= http.StatusBadRequest {
+				result.Error = fmt.Sprintf("Conversion event failed with status %v", element.Status)
+			}
+		}
+	}
+
+	return &results, nil
+}
@@ -6,6 +6,8 @@ import (
 	go_http "github.com/leapforce-libraries/go_http"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 )
 
 type ConversionsResponse struct {
@@ -14,23 +16,55 @@ type ConversionsResponse struct {
 }
 
 type Conversion struct {
-	PostClickAttributionWindowSize   *int64                          `json:"postClickAttributionWindowSize"`
-	ViewThroughAttributionWindowSize *int64                          `json:"viewThroughAttributionWindowSize"`
-	Created                          *int64                          `json:"created"`
-	ImagePixelTag                    *string                         `json:"imagePixelTag"`
-	Type                             *string                         `json:"type"`
-	Enabled                          *bool                           `json:"enabled"`
-	AssociatedCampaigns              *[]ConversionAssociatedCampaign `json:"associatedCampaigns"`
-	Campaigns                        *[]string                       `json:"campaigns"`
-	Name                             *string                         `json:"name"`
-	LastModified                     *int64                          `json:"lastModified"`
-	Id                               *int64                          `json:"id"`
-	AttributionType                  *string                         `json:"attributionType"`
-	UrlRules                         *[]UrlRule                      `json:"urlRules"`
-	Value                            *ConversionValue                `json:"value"`
-	Account                          *string                         `json:"account"`
+	PostClickAttributionWindowSize   *int64                          `json:"postClickAttributionWindowSize,omitempty"`
+	ViewThroughAttributionWindowSize *int64                          `json:"viewThroughAttributionWindowSize,omitempty"`
+	Created                          *int64                          `json:"created,omitempty"`
+	ImagePixelTag                    *string                         `json:"imagePixelTag,omitempty"`
+	Type                             *string                         `json:"type,omitempty"`
+	Enabled                          *bool                           `json:"enabled,omitempty"`
+	AssociatedCampaigns              *[]ConversionAssociatedCampaign `json:"associatedCampaigns,omitempty"`
+	Campaigns                        *[]string                       `json:"campaigns,omitempty"`
+	Name                             *string                         `json:"name,omitempty"`
+	LastModified                     *int64                          `json:"lastModified,omitempty"`
+	Id                               *int64                          `json:"id,omitempty"`
+	AttributionType                  *string                         `json:"attributionType,omitempty"`
+	UrlRules                         *[]UrlRule                      `json:"urlRules,omitempty"`
+	Value                            *ConversionValue                `json:"value,omitempty"`
+	Account                          *string                         `json:"account,omitempty"`
 }
 
+const (
+	ConversionTypeAddToCart         string = "ADD_TO_CART"
+	ConversionTypeDownload          string = "DOWNLOAD"
+	ConversionTypeInstall           string = "INSTALL"
+	ConversionTypeKeyPageView       string = "KEY_PAGE_VIEW"
+	ConversionTypeLead              string = "LEAD"
+	ConversionTypePurchase          string = "PURCHASE"
+	ConversionTypeSignUp            string = "SIGN_UP"
+	ConversionTypeOther             string = "OTHER"
+	ConversionTypeBookAppointment   string = "BOOK_APPOINTMENT"
+	ConversionTypeRequestQuote      string = "REQUEST_QUOTE"
+	ConversionTypeSearch            string = "SEARCH"
+	ConversionTypeSubmitApplication string = "SUBMIT_APPLICATION"
+	ConversionTypeSubscribe         string = "SUBSCRIBE"
+	ConversionTypeStartCheckout     string = "START_CHECKOUT"
+	ConversionTypeAddBillingInfo    string = "ADD_BILLING_INFO"
+	ConversionTypeContact           string = "CONTACT"
+	ConversionTypeQualifiedLead     string = "QUALIFIED_LEAD"
+	ConversionTypeOutboundClick     string = "OUTBOUND_CLICK"
+	ConversionTypePhoneCall         string = "PHONE_CALL"
+
+	ConversionAttributionTypeLastTouchByCampaign   string = "LAST_TOUCH_BY_CAMPAIGN"
+	ConversionAttributionTypeLastTouchByConversion string = "LAST_TOUCH_BY_CONVERSION"
+
+	UrlRuleTypeExact      string = "EXACT"
+	UrlRuleTypeStartsWith string = "STARTS_WITH"
+	UrlRuleTypeContains   string = "CONTAINS"
+)
+
+// valid values for PostClickAttributionWindowSize and ViewThroughAttributionWindowSize
+var conversionAttributionWindowSizes = map[int64]bool{1: true, 7: true, 30: true, 90: true}
+
 type ConversionAssociatedCampaign struct {
 	AssociatedAt int64  `json:"associatedAt"`
 	Campaign     string `json:"campaign"`
@@ -118,3 +152,226 @@ func (service *Service) GetConversionsForAccount(config *GetConversionsConfig) (
 
 	return &conversions, nil
 }
+
+func validateConversionAttributionWindowSize(name string, size *int64) *errortools.Error {
+	if size != nil && !conversionAttributionWindowSizes[*size] {
+		return errortools.ErrorMessagef("%s must be 1, 7, 30 or 90 days", name)
+	}
+
+	return nil
+}
+
+func validateUrlRules(urlRules *[]UrlRule) *errortools.Error {
+	if urlRules == nil {
+		return nil
+	}
+	for _, urlRule := range *urlRules {
+		if urlRule.MatchValue == "" {
+			return errortools.ErrorMessage("UrlRule matchValue is required")
+		}
+		switch urlRule.Type {
+		case UrlRuleTypeExact, UrlRuleTypeStartsWith, UrlRuleTypeContains:
+		default:
+			return errortools.ErrorMessagef("UrlRule type '%s' is invalid", urlRule.Type)
+		}
+	}
+
+	return nil
+}
+
+// CreateConversion creates a conversion rule for the account and returns its id
+func (service *Service) CreateConversion(accountId int64, conversion *Conversion) (int64, *errortools.Error) {
+	if service == nil {
+		return 0, errortools.ErrorMessage("Service pointer is nil")
+	}
+	if conversion == nil {
+		return 0, errortools.ErrorMessage("Conversion pointer is nil")
+	}
+	if conversion.Name == nil || *conversion.Name == "" {
+		return 0, errortools.ErrorMessage("Conversion name is required")
+	}
+	if conversion.Type == nil || *conversion.Type == "" {
+		return 0, errortools.ErrorMessage("Conversion type is required")
+	}
+	e := validateConversionAttributionWindowSize("PostClickAttributionWindowSize", conversion.PostClickAttributionWindowSize)
+	if e != nil {
+		return 0, e
+	}
+	e = validateConversionAttributionWindowSize("ViewThroughAttributionWindowSize", conversion.ViewThroughAttributionWindowSize)
+	if e != nil {
+		return 0, e
+	}
+	e = validateUrlRules(conversion.UrlRules)
+	if e != nil {
+		return 0, e
+	}
+
+	account := fmt.Sprintf("%s%v", AccountUrnPrefix, accountId)
+
+	_conversion := *conversion
+	_conversion.Account = &account
+	// read-only fields
+	_conversion.Id = nil
+	_conversion.Created = nil
+	_conversion.LastModified = nil
+	_conversion.ImagePixelTag = nil
+	_conversion.AssociatedCampaigns = nil
+
+	var header = http.Header{}
+	header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+
+	requestConfig := go_http.RequestConfig{
+		Method:            http.MethodPost,
+		Url:               service.urlRest("conversions"),
+		BodyModel:         _conversion,
+		NonDefaultHeaders: &header,
+	}
+	_, resp, e := service.versionedHttpRequest(&requestConfig, nil)
+	if e != nil {
+		return 0, e
+	}
+
+	var conversionId = resp.Header.Get("X-Restli-Id")
+	if conversionId == "" {
+		conversionId = resp.Header.Get("X-Linkedin-Id")
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(conversionId, ConversionUrnPrefix), 10, 64)
+	if err != nil {
+		return 0, errortools.ErrorMessagef("CreateConversion did not return a valid conversion id in header: '%s'", conversionId)
+	}
+
+	return id, nil
+}
+
+// UpdateConversionConfig holds the fields to update, nil fields are left unchanged
+type UpdateConversionConfig struct {
+	Name                             *string
+	Type                             *string
+	Enabled                          *bool
+	AttributionType                  *string
+	PostClickAttributionWindowSize   *int64
+	ViewThroughAttributionWindowSize *int64
+	UrlRules                         *[]UrlRule
+	Value                            *ConversionValue
+}
+
+// UpdateConversion partially updates the conversion rule
+func (service *Service) UpdateConversion(conversionId int64, config *UpdateConversionConfig) *errortools.Error {
+	if service == nil {
+		return errortools.ErrorMessage("Service pointer is nil")
+	}
+	if config == nil {
+		return errortools.ErrorMessage("UpdateConversionConfig must not be nil")
+	}
+	e := validateConversionAttributionWindowSize("PostClickAttributionWindowSize", config.PostClickAttributionWindowSize)
+	if e != nil {
+		return e
+	}
+	e = validateConversionAttributionWindowSize("ViewThroughAttributionWindowSize", config.ViewThroughAttributionWindowSize)
+	if e != nil {
+		return e
+	}
+	e = validateUrlRules(config.UrlRules)
+	if e != nil {
+		return e
+	}
+
+	set := make(map[string]interface{})
+	if config.Name != nil {
+		set["name"] = *config.Name
+	}
+	if config.Type != nil {
+		set["type"] = *config.Type
+	}
+	if config.Enabled != nil {
+		set["enabled"] = *config.Enabled
+	}
+	if config.AttributionType != nil {
+		set["attributionType"] = *config.AttributionType
+	}
+	if config.PostClickAttributionWindowSize != nil {
+		set["postClickAttributionWindowSize"] = *config.PostClickAttributionWindowSize
+	}
+	if config.ViewThroughAttributionWindowSize != nil {
+		set["viewThroughAttributionWindowSize"] = *config.ViewThroughAttributionWindowSize
+	}
+	if config.UrlRules != nil {
+		set["urlRules"] = *config.UrlRules
+	}
+	if config.Value != nil {
+		set["value"] = *config.Value
+	}
+	if len(set) == 0 {
+		return errortools.ErrorMessage("UpdateConversionConfig contains no fields to update")
+	}
+
+	var header = http.Header{}
+	header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+	header.Set("X-RestLi-Method", "PARTIAL_UPDATE")
+
+	requestConfig := go_http.RequestConfig{
+		Method: http.MethodPost,
+		Url:    service.urlRest(fmt.Sprintf("conversions/%v", conversionId)),
+		BodyModel: map[string]interface{}{
+			"patch": map[string]interface{}{
+				"$set": set,
+			},
+		},
+		NonDefaultHeaders: &header,
+	}
+	_, _, e = service.versionedHttpRequest(&requestConfig, nil)
+
+	return e
+}
+
+func (service *Service) campaignConversionUrl(campaignId int64, conversionId int64) string {
+	return service.urlRest(fmt.Sprintf("campaignConversions/(campaign:%s,conversion:%s)",
+		url.QueryEscape(fmt.Sprintf("%s%v", CampaignUrnPrefix, campaignId)),
+		url.QueryEscape(fmt.Sprintf("%s%v", ConversionUrnPrefix, conversionId)),
+	))
+}
+
+// AssociateConversionWithCampaign makes the campaign track the conversion
+func (service *Service) AssociateConversionWithCampaign(conversionId int64, campaignId int64) *errortools.Error {
+	if service == nil {
+		return errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	var header = http.Header{}
+	header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+
+	requestConfig := go_http.RequestConfig{
+		Method: http.MethodPut,
+		Url:    service.campaignConversionUrl(campaignId, conversionId),
+		BodyModel: struct {
+			Campaign   string `json:"campaign"`
+			Conversion string `json:"conversion"`
+		}{
+			fmt.Sprintf("%s%v", CampaignUrnPrefix, campaignId),
+			fmt.Sprintf("%s%v", ConversionUrnPrefix, conversionId),
+		},
+		NonDefaultHeaders: &header,
+	}
+	_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+
+	return e
+}
+
+func (service *Service) DisassociateConversionFromCampaign(conversionId int64, campaignId int64) *errortools.Error {
+	if service == nil {
+		return errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	var header = http.Header{}
+	header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+	header.Set("X-RestLi-Method", "DELETE")
+
+	requestConfig := go_http.RequestConfig{
+		Method:            http.MethodDelete,
+		Url:               service.campaignConversionUrl(campaignId, conversionId),
+		NonDefaultHeaders: &header,
+	}
+	_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+
+	return e
+}
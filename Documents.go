@@ -0,0 +1,371 @@
+package linkedin
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	errortools "github.com/leapforce-libraries/go_errortools"
+	go_http "github.com/leapforce-libraries/go_http"
+)
+
+const (
+	MaxDocumentSize  int64 = 100 << 20 // bytes
+	MaxDocumentPages int   = 300
+)
+
+type DocumentType string
+
+const (
+	DocumentTypePdf  DocumentType = "PDF"
+	DocumentTypeDoc  DocumentType = "DOC" // also used for legacy PPT, both are OLE2 compound files
+	DocumentTypeDocx DocumentType = "DOCX"
+	DocumentTypePptx DocumentType = "PPTX"
+)
+
+type InitializeUploadDocumentRequest struct {
+	Owner string `json:"owner"`
+}
+
+type InitializeUploadDocumentResponse struct {
+	Value struct {
+		UploadUrlExpiresAt int64  `json:"uploadUrlExpiresAt"`
+		UploadUrl          string `json:"uploadUrl"`
+		Document           string `json:"document"`
+	} `json:"value"`
+}
+
+func (service *Service) InitializeUploadDocument(owner string) (*InitializeUploadDocumentResponse, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	var initializeUploadRequest = struct {
+		InitializeUploadDocumentRequest `json:"initializeUploadRequest"`
+	}{InitializeUploadDocumentRequest{owner}}
+
+	var initializeUploadResponse InitializeUploadDocumentResponse
+
+	var header = http.Header{}
+	header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+
+	requestConfig := go_http.RequestConfig{
+		Method:            http.MethodPost,
+		Url:               service.urlRest("documents?action=initializeUpload"),
+		BodyModel:         initializeUploadRequest,
+		ResponseModel:     &initializeUploadResponse,
+		NonDefaultHeaders: &header,
+	}
+	_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+	if e != nil {
+		return nil, e
+	}
+	return &initializeUploadResponse, nil
+}
+
+var (
+	oleSignature      = []byte{0xd0, 0xcf, 0x11, 0xe0, 0xa1, 0xb1, 0x1a, 0xe1}
+	pdfPageRegexp     = regexp.MustCompile(`/Type\s*/Page\b`)
+	pdfPagesRegexp    = regexp.MustCompile(`<<[^<>]*/Type\s*/Pages\b[^<>]*>>`)
+	pdfCountRegexp    = regexp.MustCompile(`/Count\s+(\d+)`)
+	pptxSlideRegexp   = regexp.MustCompile(`^ppt/slides/slide\d+\.xml$`)
+	documentMimeTypes = map[DocumentType]string{
+		DocumentTypePdf:  "application/pdf",
+		DocumentTypeDoc:  "application/octet-stream",
+		DocumentTypeDocx: "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		DocumentTypePptx: "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	}
+)
+
+// ValidateDocument detects the type of a document and checks its size and, where it can be determined, its page count.
+// Page counting is best-effort: PDF page objects inside compressed object streams (PDF 1.5+) and DOC files cannot be counted,
+// for those the page count is not checked.
+func ValidateDocument(b []byte) (DocumentType, *errortools.Error) {
+	if len(b) == 0 {
+		return "", errortools.ErrorMessage("Document is empty")
+	}
+	if int64(len(b)) > MaxDocumentSize {
+		return "", errortools.ErrorMessagef("Document exceeds the maximum size of %v bytes", MaxDocumentSize)
+	}
+
+	var documentType DocumentType
+	var pages int
+
+	switch {
+	case bytes.HasPrefix(b, []byte("%PDF-")):
+		documentType = DocumentTypePdf
+		pages = pdfPages(b)
+	case bytes.HasPrefix(b, oleSignature):
+		documentType = DocumentTypeDoc
+	case bytes.HasPrefix(b, []byte("PK\x03\x04")):
+		reader, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+		if err != nil {
+			return "", errortools.ErrorMessagef("Invalid document: %s", err.Error())
+		}
+		for _, file := range reader.File {
+			switch {
+			case strings.HasPrefix(file.Name, "word/"):
+				documentType = DocumentTypeDocx
+			case strings.HasPrefix(file.Name, "ppt/"):
+				documentType = DocumentTypePptx
+				if pptxSlideRegexp.MatchString(file.Name) {
+					pages++
+				}
+			case file.Name == "docProps/app.xml":
+				if n := officePages(file); n > pages {
+					pages = n
+				}
+			}
+		}
+		if documentType == "" {
+			return "", errortools.ErrorMessage("Unsupported document, supported are PDF, DOC, DOCX, PPT and PPTX")
+		}
+	default:
+		return "", errortools.ErrorMessage("Unsupported document, supported are PDF, DOC, DOCX, PPT and PPTX")
+	}
+
+	// pages is 0 when the count could not be determined, then the check is skipped
+	if pages > MaxDocumentPages {
+		return "", errortools.ErrorMessagef("Document has %v pages, the maximum is %v", pages, MaxDocumentPages)
+	}
+
+	return documentType, nil
+}
+
+// pdfPages counts the page objects of a PDF, or takes the /Count of its page tree if that is higher,
+// it returns 0 if neither is found outside compressed object streams
+func pdfPages(b []byte) int {
+	pages := len(pdfPageRegexp.FindAllIndex(b, -1))
+
+	for _, dictionary := range pdfPagesRegexp.FindAll(b, -1) {
+		match := pdfCountRegexp.FindSubmatch(dictionary)
+		if match == nil {
+			continue
+		}
+		if count, err := strconv.Atoi(string(match[1])); err == nil && count > pages {
+			pages = count
+		}
+	}
+
+	return pages
+}
+
+// officePages reads the page or slide count from the docProps/app.xml of an Office Open XML document
+func officePages(file *zip.File) int {
+	reader, err := file.Open()
+	if err != nil {
+		return 0
+	}
+	defer reader.Close()
+
+	var properties struct {
+		Pages  int `xml:"Pages"`
+		Slides int `xml:"Slides"`
+	}
+	if xml.NewDecoder(reader).Decode(&properties) != nil {
+		return 0
+	}
+	if properties.Slides > properties.Pages {
+		return properties.Slides
+	}
+
+	return properties.Pages
+}
+
+// UploadDocumentFromReader uploads size bytes read from reader to the putUrl returned by InitializeUploadDocument
+func (service *Service) UploadDocumentFromReader(putUrl string, reader io.Reader, size int64) *errortools.Error {
+	if service == nil {
+		return errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	b, e := readMedia(reader, size, MaxDocumentSize)
+	if e != nil {
+		return e
+	}
+
+	return service.uploadDocument(putUrl, b)
+}
+
+func (service *Service) uploadDocument(putUrl string, b []byte) *errortools.Error {
+	documentType, e := ValidateDocument(b)
+	if e != nil {
+		return e
+	}
+
+	var header = http.Header{}
+	header.Set("Content-Type", documentMimeTypes[documentType])
+
+	requestConfig := go_http.RequestConfig{
+		Method:            http.MethodPut,
+		Url:               putUrl,
+		BodyRaw:           &b,
+		NonDefaultHeaders: &header,
+	}
+	_, _, e = service.versionedHttpRequest(&requestConfig, nil)
+
+	return e
+}
+
+type Document struct {
+	Owner                string      `json:"owner"`
+	Status               MediaStatus `json:"status"`
+	Id                   string      `json:"id"`
+	DownloadUrl          string      `json:"downloadUrl,omitempty"`
+	DownloadUrlExpiresAt int64       `json:"downloadUrlExpiresAt,omitempty"`
+}
+
+// PostContent returns the content for a post sharing the document, title being shown above the document
+func (document *Document) PostContent(title string) *PostContent {
+	return &PostContent{
+		Media: &PostContentMedia{
+			Title: title,
+			Id:    document.Id,
+		},
+	}
+}
+
+func (service *Service) GetDocument(documentUrn string) (*Document, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	var header = http.Header{}
+	header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+
+	var document Document
+
+	requestConfig := go_http.RequestConfig{
+		Method:            http.MethodGet,
+		Url:               service.urlRest(fmt.Sprintf("documents/%s", url.QueryEscape(documentUrn))),
+		ResponseModel:     &document,
+		NonDefaultHeaders: &header,
+	}
+	_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+	if e != nil {
+		return nil, e
+	}
+
+	return &document, nil
+}
+
+type DocumentsResponse struct {
+	Results map[string]Document `json:"results"`
+}
+
+func (service *Service) BatchGetDocuments(documentUrns []string) (*[]Document, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	var documents []Document
+
+	for _, _urnsBatch := range urnBatches(documentUrns) {
+		documentsResponse := DocumentsResponse{}
+
+		e := service.batchGet("documents", _urnsBatch, &documentsResponse)
+		if e != nil {
+			return nil, e
+		}
+
+		// results are returned in the order of the urns
+		for _, urn := range _urnsBatch {
+			document, ok := documentsResponse.Results[urn]
+			if !ok {
+				continue
+			}
+			if document.Id == "" {
+				document.Id = urn
+			}
+			documents = append(documents, document)
+		}
+	}
+
+	return &documents, nil
+}
+
+// WaitForDocument polls the status of the document until it is AVAILABLE
+func (service *Service) WaitForDocument(documentUrn string, config *WaitForMediaConfig) (*Document, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	var document *Document
+
+	e := waitForMedia(documentUrn, config, func() (MediaStatus, *errortools.Error) {
+		var e *errortools.Error
+		document, e = service.GetDocument(documentUrn)
+		if e != nil {
+			return "", e
+		}
+		return document.Status, nil
+	})
+	if e != nil {
+		return nil, e
+	}
+
+	return document, nil
+}
+
+// UploadDocumentAndWaitConfig requires one of Reader (with Size) or Path
+type UploadDocumentAndWaitConfig struct {
+	Owner  string
+	Reader io.Reader
+	Size   int64
+	Path   string
+	Wait   *WaitForMediaConfig
+}
+
+// UploadDocumentAndWait initializes the upload, uploads the document and waits until it is AVAILABLE
+func (service *Service) UploadDocumentAndWait(config *UploadDocumentAndWaitConfig) (*Document, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+	if config == nil {
+		return nil, errortools.ErrorMessage("UploadDocumentAndWaitConfig must not be a nil pointer")
+	}
+
+	var b []byte
+	var e *errortools.Error
+
+	switch {
+	case config.Reader != nil:
+		b, e = readMedia(config.Reader, config.Size, MaxDocumentSize)
+	case config.Path != "":
+		file, size, e_ := openMedia(config.Path)
+		if e_ != nil {
+			return nil, e_
+		}
+		defer file.Close()
+		b, e = readMedia(file, size, MaxDocumentSize)
+	default:
+		return nil, errortools.ErrorMessage("One of Reader or Path must be set")
+	}
+	if e != nil {
+		return nil, e
+	}
+
+	// validate before initializing the upload
+	_, e = ValidateDocument(b)
+	if e != nil {
+		return nil, e
+	}
+
+	initializeUploadDocumentResponse, e := service.InitializeUploadDocument(config.Owner)
+	if e != nil {
+		return nil, e
+	}
+
+	e = service.uploadDocument(initializeUploadDocumentResponse.Value.UploadUrl, b)
+	if e != nil {
+		return nil, e
+	}
+
+	return service.WaitForDocument(initializeUploadDocumentResponse.Value.Document, config.Wait)
+}
@@ -4,8 +4,11 @@ import (
 	"fmt"
 	errortools "github.com/leapforce-libraries/go_errortools"
 	go_http "github.com/leapforce-libraries/go_http"
+	"image"
 	"io"
 	"net/http"
+	"net/url"
+	"time"
 )
 
 type InitializeUploadImageRequest struct {
@@ -49,54 +52,119 @@ func (service *Service) InitializeUploadImage(owner string) (*InitializeUploadIm
 	return &initializeUploadResponse, nil
 }
 
+// UploadImage downloads the image at imageUrl and uploads it to the putUrl returned by InitializeUploadImage
 func (service *Service) UploadImage(putUrl string, imageUrl string) *errortools.Error {
 	if service == nil {
 		return errortools.ErrorMessage("Service pointer is nil")
 	}
 
-	resp, err := http.Get(imageUrl)
-	if err != nil {
-		return errortools.ErrorMessage(err)
+	b, e := downloadMedia(imageUrl, MaxImageSize)
+	if e != nil {
+		return e
 	}
 
-	defer resp.Body.Close()
+	return service.uploadImage(putUrl, b)
+}
+
+// UploadImageFromReader uploads size bytes read from reader to the putUrl returned by InitializeUploadImage
+func (service *Service) UploadImageFromReader(putUrl string, reader io.Reader, size int64) *errortools.Error {
+	if service == nil {
+		return errortools.ErrorMessage("Service pointer is nil")
+	}
 
-	bytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return errortools.ErrorMessage(err)
+	b, e := readMedia(reader, size, MaxImageSize)
+	if e != nil {
+		return e
+	}
+
+	return service.uploadImage(putUrl, b)
+}
+
+// UploadImageFromFile uploads the image file at path to the putUrl returned by InitializeUploadImage
+func (service *Service) UploadImageFromFile(putUrl string, path string) *errortools.Error {
+	if service == nil {
+		return errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	file, size, e := openMedia(path)
+	if e != nil {
+		return e
+	}
+
+	defer file.Close()
+
+	return service.UploadImageFromReader(putUrl, file, size)
+}
+
+func (service *Service) uploadImage(putUrl string, b []byte) *errortools.Error {
+	_, e := service.uploadImageWithConfig(putUrl, b)
+
+	return e
+}
+
+// uploadImageWithConfig uploads the image and returns its dimensions
+func (service *Service) uploadImageWithConfig(putUrl string, b []byte) (*image.Config, *errortools.Error) {
+	contentType, config, e := validateImage(b)
+	if e != nil {
+		return nil, e
 	}
 
 	var header = http.Header{}
-	header.Set("Content-Type", http.DetectContentType(bytes))
+	header.Set("Content-Type", contentType)
 
 	requestConfig := go_http.RequestConfig{
 		Method:            http.MethodPut,
 		Url:               putUrl,
-		BodyRaw:           &bytes,
+		BodyRaw:           &b,
 		NonDefaultHeaders: &header,
 	}
-	_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+	_, _, e = service.versionedHttpRequest(&requestConfig, nil)
+	if e != nil {
+		return nil, e
+	}
 
-	return e
+	return config, nil
 }
 
 type Image struct {
-	Owner  string `json:"owner"`
-	Status string `json:"status"`
-	Id     string `json:"id"`
+	Owner                string      `json:"owner"`
+	Status               MediaStatus `json:"status"`
+	Id                   string      `json:"id"`
+	DownloadUrl          string      `json:"downloadUrl,omitempty"`
+	DownloadUrlExpiresAt int64       `json:"downloadUrlExpiresAt,omitempty"`
+	// Width and Height are not returned by the API, UploadImageAndWait sets them from the uploaded file
+	Width  int `json:"-"`
+	Height int `json:"-"`
+}
+
+// DownloadUrlExpiresAtTime returns the time at which DownloadUrl expires
+func (image *Image) DownloadUrlExpiresAtTime() time.Time {
+	return time.UnixMilli(image.DownloadUrlExpiresAt)
 }
 
+// GetImage returns the image, fields is an optional comma separated list of fields to return
 func (service *Service) GetImage(imageUrn string, fields string) (*Image, *errortools.Error) {
 	if service == nil {
 		return nil, errortools.ErrorMessage("Service pointer is nil")
 	}
 
+	path := fmt.Sprintf("images/%s", url.QueryEscape(imageUrn))
+	if fields != "" {
+		var values = url.Values{}
+		values.Set("fields", fields)
+		path = fmt.Sprintf("%s?%s", path, values.Encode())
+	}
+
+	var header = http.Header{}
+	header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+
 	var image Image
 
 	requestConfig := go_http.RequestConfig{
-		Method:        http.MethodGet,
-		Url:           service.urlRest(fmt.Sprintf("images/%s?fields=%s", imageUrn, fields)),
-		ResponseModel: &image,
+		Method:            http.MethodGet,
+		Url:               service.urlRest(path),
+		ResponseModel:     &image,
+		NonDefaultHeaders: &header,
 	}
 	_, _, e := service.versionedHttpRequest(&requestConfig, nil)
 	if e != nil {
@@ -105,3 +173,182 @@ func (service *Service) GetImage(imageUrn string, fields string) (*Image, *error
 
 	return &image, nil
 }
+
+// WaitForImage polls the status of the image until it is AVAILABLE
+func (service *Service) WaitForImage(imageUrn string, config *WaitForMediaConfig) (*Image, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	var image *Image
+
+	e := waitForMedia(imageUrn, config, func() (MediaStatus, *errortools.Error) {
+		var e *errortools.Error
+		image, e = service.GetImage(imageUrn, "")
+		if e != nil {
+			return "", e
+		}
+		return image.Status, nil
+	})
+	if e != nil {
+		return nil, e
+	}
+
+	return image, nil
+}
+
+// UploadImageAndWaitConfig requires one of Reader (with Size), Path or Url
+type UploadImageAndWaitConfig struct {
+	Owner  string
+	Reader io.Reader
+	Size   int64
+	Path   string
+	Url    string
+	Wait   *WaitForMediaConfig
+}
+
+// UploadImageAndWait initializes the upload, uploads the image and waits until it is AVAILABLE
+func (service *Service) UploadImageAndWait(config *UploadImageAndWaitConfig) (*Image, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+	if config == nil {
+		return nil, errortools.ErrorMessage("UploadImageAndWaitConfig must not be a nil pointer")
+	}
+
+	var b []byte
+	var e *errortools.Error
+
+	switch {
+	case config.Reader != nil:
+		b, e = readMedia(config.Reader, config.Size, MaxImageSize)
+	case config.Path != "":
+		file, size, e_ := openMedia(config.Path)
+		if e_ != nil {
+			return nil, e_
+		}
+		defer file.Close()
+		b, e = readMedia(file, size, MaxImageSize)
+	case config.Url != "":
+		b, e = downloadMedia(config.Url, MaxImageSize)
+	default:
+		return nil, errortools.ErrorMessage("One of Reader, Path or Url must be set")
+	}
+	if e != nil {
+		return nil, e
+	}
+
+	// validate before initializing the upload
+	_, _, e = validateImage(b)
+	if e != nil {
+		return nil, e
+	}
+
+	initializeUploadImageResponse, e := service.InitializeUploadImage(config.Owner)
+	if e != nil {
+		return nil, e
+	}
+
+	imageConfig, e := service.uploadImageWithConfig(initializeUploadImageResponse.Value.UploadUrl, b)
+	if e != nil {
+		return nil, e
+	}
+
+	image, e := service.WaitForImage(initializeUploadImageResponse.Value.Image, config.Wait)
+	if e != nil {
+		return nil, e
+	}
+
+	image.Width = imageConfig.Width
+	image.Height = imageConfig.Height
+
+	return image, nil
+}
+
+type ImagesResponse struct {
+	Results map[string]Image `json:"results"`
+}
+
+// BatchGetImages returns the images for the given urns in their order, requested in batches of at most 50
+func (service *Service) BatchGetImages(imageUrns []string) (*[]Image, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	var images []Image
+
+	for _, _urnsBatch := range urnBatches(imageUrns) {
+		imagesResponse := ImagesResponse{}
+
+		e := service.batchGet("images", _urnsBatch, &imagesResponse)
+		if e != nil {
+			return nil, e
+		}
+
+		// results are returned in the order of the urns
+		for _, urn := range _urnsBatch {
+			_image, ok := imagesResponse.Results[urn]
+			if !ok {
+				continue
+			}
+			if _image.Id == "" {
+				_image.Id = urn
+			}
+			images = append(images, _image)
+		}
+	}
+
+	return &images, nil
+}
+
+type ImagesByOwnerResponse struct {
+	Paging   Paging  `json:"paging"`
+	Elements []Image `json:"elements"`
+}
+
+// GetImagesByOwner returns all images owned by owner, e.g. an organization (urn:li:organization) or member (urn:li:person)
+func (service *Service) GetImagesByOwner(owner string) (*[]Image, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	var start uint = 0
+	var count uint = countDefault
+
+	var values = url.Values{}
+	values.Set("q", "owner")
+	values.Set("owner", owner)
+	values.Set("count", fmt.Sprintf("%v", count))
+
+	var images []Image
+
+	for {
+		values.Set("start", fmt.Sprintf("%v", start))
+
+		var header = http.Header{}
+		header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+
+		var imagesByOwnerResponse ImagesByOwnerResponse
+
+		requestConfig := go_http.RequestConfig{
+			Method:            http.MethodGet,
+			Url:               service.urlRest(fmt.Sprintf("images?%s", values.Encode())),
+			ResponseModel:     &imagesByOwnerResponse,
+			NonDefaultHeaders: &header,
+		}
+		_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+		if e != nil {
+			return nil, e
+		}
+
+		images = append(images, imagesByOwnerResponse.Elements...)
+
+		if len(imagesByOwnerResponse.Elements) < int(count) {
+			break
+		}
+
+		start += count
+	}
+
+	return &images, nil
+}
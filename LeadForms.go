@@ -0,0 +1,558 @@
+package linkedin
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	errortools "github.com/leapforce-libraries/go_errortools"
+	go_http "github.com/leapforce-libraries/go_http"
+)
+
+const leadFormResponsesPageSize uint = 100
+
+// LeadFormOwner is either a sponsored account or an organization
+type LeadFormOwner struct {
+	SponsoredAccount string `json:"sponsoredAccount,omitempty"`
+	Organization     string `json:"organization,omitempty"`
+}
+
+func NewSponsoredAccountLeadFormOwner(accountId int64) LeadFormOwner {
+	return LeadFormOwner{SponsoredAccount: fmt.Sprintf("%s%v", AccountUrnPrefix, accountId)}
+}
+
+func NewOrganizationLeadFormOwner(organizationId int64) LeadFormOwner {
+	return LeadFormOwner{Organization: fmt.Sprintf("%s%v", OrganizationUrnPrefix, organizationId)}
+}
+
+// restli returns the owner as Rest.li 2.0 query parameter value
+func (owner *LeadFormOwner) restli() (string, *errortools.Error) {
+	if owner.SponsoredAccount != "" && owner.Organization == "" {
+		return fmt.Sprintf("(sponsoredAccount:%s)", url.QueryEscape(owner.SponsoredAccount)), nil
+	}
+	if owner.Organization != "" && owner.SponsoredAccount == "" {
+		return fmt.Sprintf("(organization:%s)", url.QueryEscape(owner.Organization)), nil
+	}
+
+	return "", errortools.ErrorMessage("LeadFormOwner must have exactly one of SponsoredAccount or Organization")
+}
+
+func (owner *LeadFormOwner) String() string {
+	if owner.SponsoredAccount != "" {
+		return owner.SponsoredAccount
+	}
+
+	return owner.Organization
+}
+
+// LeadFormLocalizedString holds a text in one or more locales, keyed like en_US
+type LeadFormLocalizedString struct {
+	Localized       map[string]string `json:"localized"`
+	PreferredLocale *AdLocale         `json:"preferredLocale,omitempty"`
+}
+
+// Value returns the text in the preferred locale, falling back to en_US and then to any locale
+func (localizedString *LeadFormLocalizedString) Value() string {
+	if localizedString == nil || len(localizedString.Localized) == 0 {
+		return ""
+	}
+	if localizedString.PreferredLocale != nil {
+		value, ok := localizedString.Localized[fmt.Sprintf("%s_%s", localizedString.PreferredLocale.Language, localizedString.PreferredLocale.Country)]
+		if ok {
+			return value
+		}
+	}
+	value, ok := localizedString.Localized["en_US"]
+	if ok {
+		return value
+	}
+
+	var locales []string
+	for locale := range localizedString.Localized {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	return localizedString.Localized[locales[0]]
+}
+
+type LeadFormState string
+
+const (
+	LeadFormStateDraft     LeadFormState = "DRAFT"
+	LeadFormStatePublished LeadFormState = "PUBLISHED"
+	LeadFormStateArchived  LeadFormState = "ARCHIVED"
+)
+
+type LeadFormsResponse struct {
+	Paging   Paging     `json:"paging"`
+	Elements []LeadForm `json:"elements"`
+}
+
+type LeadForm struct {
+	Id             int64           `json:"id"`
+	Owner          LeadFormOwner   `json:"owner"`
+	Name           string          `json:"name"`
+	State          LeadFormState   `json:"state"`
+	VersionId      int64           `json:"versionId"`
+	Created        int64           `json:"created"`
+	LastModified   int64           `json:"lastModified"`
+	CreationLocale *AdLocale       `json:"creationLocale,omitempty"`
+	Content        LeadFormContent `json:"content"`
+}
+
+type LeadFormContent struct {
+	Headline           *LeadFormLocalizedString    `json:"headline,omitempty"`
+	Description        *LeadFormLocalizedString    `json:"description,omitempty"`
+	Questions          []LeadFormQuestion          `json:"questions"`
+	HiddenFields       []LeadFormHiddenField       `json:"hiddenFields,omitempty"`
+	LegalInfo          *LeadFormLegalInfo          `json:"legalInfo,omitempty"`
+	PostSubmissionInfo *LeadFormPostSubmissionInfo `json:"postSubmissionInfo,omitempty"`
+}
+
+type LeadFormQuestion struct {
+	QuestionId      int64                    `json:"questionId"`
+	Name            string                   `json:"name"`
+	Question        *LeadFormLocalizedString `json:"question,omitempty"`
+	PredefinedField string                   `json:"predefinedField,omitempty"` // e.g. FIRST_NAME, EMAIL, COMPANY_NAME
+	QuestionDetails LeadFormQuestionDetails  `json:"questionDetails"`
+}
+
+// Label returns the question text, or its name if it has no text
+func (question *LeadFormQuestion) Label() string {
+	label := question.Question.Value()
+	if label == "" {
+		return question.Name
+	}
+
+	return label
+}
+
+type LeadFormQuestionDetails struct {
+	TextQuestionDetails *struct {
+		MaxResponseLength int64 `json:"maxResponseLength"`
+	} `json:"textQuestionDetails,omitempty"`
+	MultipleChoiceQuestionDetails *struct {
+		Options []LeadFormQuestionOption `json:"options"`
+	} `json:"multipleChoiceQuestionDetails,omitempty"`
+}
+
+type LeadFormQuestionOption struct {
+	Id   int64                    `json:"id"`
+	Text *LeadFormLocalizedString `json:"text,omitempty"`
+}
+
+type LeadFormHiddenField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type LeadFormLegalInfo struct {
+	LegalInfoText    *LeadFormLocalizedString `json:"legalInfoText,omitempty"`
+	PrivacyPolicyUrl string                   `json:"privacyPolicyUrl,omitempty"`
+	Consents         []LeadFormConsent        `json:"consents,omitempty"`
+}
+
+type LeadFormConsent struct {
+	ConsentId int64                    `json:"consentId"`
+	Consent   *LeadFormLocalizedString `json:"consent,omitempty"`
+	Required  bool                     `json:"required"`
+}
+
+type LeadFormPostSubmissionInfo struct {
+	LandingPageUrl string                   `json:"landingPageUrl,omitempty"`
+	Message        *LeadFormLocalizedString `json:"message,omitempty"`
+}
+
+// Question returns the question with the given id, nil if the form has no such question
+func (leadForm *LeadForm) Question(questionId int64) *LeadFormQuestion {
+	for i := range leadForm.Content.Questions {
+		if leadForm.Content.Questions[i].QuestionId == questionId {
+			return &leadForm.Content.Questions[i]
+		}
+	}
+
+	return nil
+}
+
+type GetLeadFormsConfig struct {
+	Owner LeadFormOwner
+	Start *uint
+	Count *uint
+}
+
+// GetLeadForms returns the lead forms of a sponsored account or organization
+func (service *Service) GetLeadForms(config *GetLeadFormsConfig) (*[]LeadForm, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+	if config == nil {
+		return nil, errortools.ErrorMessage("GetLeadFormsConfig must not be nil")
+	}
+
+	owner, e := config.Owner.restli()
+	if e != nil {
+		return nil, e
+	}
+
+	var start uint = 0
+	var count uint = countDefault
+
+	if config.Start != nil {
+		start = *config.Start
+	}
+	if config.Count != nil {
+		count = *config.Count
+	}
+
+	var leadForms []LeadForm
+
+	for {
+		var header = http.Header{}
+		header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+
+		var leadFormsResponse LeadFormsResponse
+
+		requestConfig := go_http.RequestConfig{
+			Method:            http.MethodGet,
+			Url:               service.urlRest(fmt.Sprintf("leadForms?q=owner&owner=%s&start=%v&count=%v", owner, start, count)),
+			ResponseModel:     &leadFormsResponse,
+			NonDefaultHeaders: &header,
+		}
+		_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+		if e != nil {
+			return nil, e
+		}
+
+		leadForms = append(leadForms, leadFormsResponse.Elements...)
+
+		if config.Start != nil {
+			break
+		}
+
+		if len(leadFormsResponse.Elements) < int(count) {
+			break
+		}
+
+		start += count
+	}
+
+	return &leadForms, nil
+}
+
+func (service *Service) GetLeadForm(leadFormId int64) (*LeadForm, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	var header = http.Header{}
+	header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+
+	var leadForm LeadForm
+
+	requestConfig := go_http.RequestConfig{
+		Method:            http.MethodGet,
+		Url:               service.urlRest(fmt.Sprintf("leadForms/%v", leadFormId)),
+		ResponseModel:     &leadForm,
+		NonDefaultHeaders: &header,
+	}
+	_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+	if e != nil {
+		return nil, e
+	}
+
+	return &leadForm, nil
+}
+
+type LeadType string
+
+const (
+	LeadTypeSponsored           LeadType = "SPONSORED"
+	LeadTypeEvent               LeadType = "EVENT"
+	LeadTypeCompany             LeadType = "COMPANY"
+	LeadTypeOrganizationProduct LeadType = "ORGANIZATION_PRODUCT"
+)
+
+type LeadFormResponsesResponse struct {
+	Paging   Paging             `json:"paging"`
+	Elements []LeadFormResponse `json:"elements"`
+}
+
+type LeadFormResponse struct {
+	Id                      string                    `json:"id"`
+	Owner                   LeadFormOwner             `json:"owner"`
+	LeadType                LeadType                  `json:"leadType"`
+	VersionedLeadGenFormUrn string                    `json:"versionedLeadGenFormUrn"`
+	Submitter               string                    `json:"submitter"`
+	SubmittedAt             int64                     `json:"submittedAt"`
+	TestLead                bool                      `json:"testLead"`
+	FormResponse            LeadFormResponseContent   `json:"formResponse"`
+	LeadMetadata            *LeadFormResponseMetadata `json:"leadMetadata,omitempty"`
+}
+
+type LeadFormResponseContent struct {
+	Answers          []LeadFormAnswer          `json:"answers"`
+	ConsentResponses []LeadFormConsentResponse `json:"consentResponses,omitempty"`
+}
+
+type LeadFormAnswer struct {
+	QuestionId    int64 `json:"questionId"`
+	AnswerDetails struct {
+		TextQuestionAnswer *struct {
+			Answer string `json:"answer"`
+		} `json:"textQuestionAnswer,omitempty"`
+		MultipleChoiceAnswer *struct {
+			Options []int64 `json:"options"`
+		} `json:"multipleChoiceAnswer,omitempty"`
+	} `json:"answerDetails"`
+}
+
+type LeadFormConsentResponse struct {
+	ConsentId int64 `json:"consentId"`
+	Accepted  bool  `json:"accepted"`
+}
+
+type LeadFormResponseMetadata struct {
+	SponsoredLeadMetadata *struct {
+		Campaign string `json:"campaign"`
+	} `json:"sponsoredLeadMetadata,omitempty"`
+}
+
+// LeadFormId returns the id of the form from the versioned form urn, e.g. urn:li:versionedLeadGenForm:(urn:li:leadGenForm:123,1)
+func (response *LeadFormResponse) LeadFormId() (int64, *errortools.Error) {
+	i := strings.Index(response.VersionedLeadGenFormUrn, LeadGenFormUrnPrefix)
+	if i < 0 {
+		return 0, errortools.ErrorMessagef("Invalid versioned lead gen form urn '%s'", response.VersionedLeadGenFormUrn)
+	}
+	id := response.VersionedLeadGenFormUrn[i+len(LeadGenFormUrnPrefix):]
+	if j := strings.IndexAny(id, ",)"); j >= 0 {
+		id = id[:j]
+	}
+
+	leadFormId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, errortools.ErrorMessagef("Invalid versioned lead gen form urn '%s'", response.VersionedLeadGenFormUrn)
+	}
+
+	return leadFormId, nil
+}
+
+// LeadAnswer is an answer mapped to the question it answers
+type LeadAnswer struct {
+	QuestionId      int64
+	Name            string
+	Label           string
+	PredefinedField string
+	Value           string   // text answer, or the selected options joined by ", "
+	Options         []string // selected options of a multiple choice question
+}
+
+// Answers maps the answers to the questions of leadForm, answers to unknown questions keep only their id and value
+func (response *LeadFormResponse) Answers(leadForm *LeadForm) []LeadAnswer {
+	var answers []LeadAnswer
+
+	for _, answer := range response.FormResponse.Answers {
+		var question *LeadFormQuestion
+		if leadForm != nil {
+			question = leadForm.Question(answer.QuestionId)
+		}
+
+		leadAnswer := LeadAnswer{QuestionId: answer.QuestionId}
+		if question != nil {
+			leadAnswer.Name = question.Name
+			leadAnswer.Label = question.Label()
+			leadAnswer.PredefinedField = question.PredefinedField
+		}
+
+		if answer.AnswerDetails.TextQuestionAnswer != nil {
+			leadAnswer.Value = answer.AnswerDetails.TextQuestionAnswer.Answer
+		}
+		if answer.AnswerDetails.MultipleChoiceAnswer != nil {
+			for _, optionId := range answer.AnswerDetails.MultipleChoiceAnswer.Options {
+				option := strconv.FormatInt(optionId, 10)
+				if question != nil && question.QuestionDetails.MultipleChoiceQuestionDetails != nil {
+					for _, o := range question.QuestionDetails.MultipleChoiceQuestionDetails.Options {
+						if o.Id == optionId {
+							option = o.Text.Value()
+							break
+						}
+					}
+				}
+				leadAnswer.Options = append(leadAnswer.Options, option)
+			}
+			leadAnswer.Value = strings.Join(leadAnswer.Options, ", ")
+		}
+
+		answers = append(answers, leadAnswer)
+	}
+
+	return answers
+}
+
+type GetLeadFormResponsesConfig struct {
+	Owner              LeadFormOwner
+	LeadType           LeadType
+	SubmittedAtStart   *int64 // unix milliseconds, inclusive
+	SubmittedAtEnd     *int64 // unix milliseconds, exclusive
+	LimitedToTestLeads *bool
+	Start              *uint
+	Count              *uint
+}
+
+// GetLeadFormResponses returns the leads of a sponsored account or organization
+func (service *Service) GetLeadFormResponses(config *GetLeadFormResponsesConfig) (*[]LeadFormResponse, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+	if config == nil {
+		return nil, errortools.ErrorMessage("GetLeadFormResponsesConfig must not be nil")
+	}
+	if config.LeadType == "" {
+		return nil, errortools.ErrorMessage("LeadType is required")
+	}
+
+	owner, e := config.Owner.restli()
+	if e != nil {
+		return nil, e
+	}
+
+	var start uint = 0
+	var count uint = countDefault
+
+	if config.Start != nil {
+		start = *config.Start
+	}
+	if config.Count != nil {
+		count = *config.Count
+	}
+
+	// Rest.li structures must not be url encoded, so the query is built manually
+	query := fmt.Sprintf("q=owner&owner=%s&leadType=(leadType:%s)", owner, config.LeadType)
+	if config.LimitedToTestLeads != nil {
+		query += fmt.Sprintf("&limitedToTestLeads=%v", *config.LimitedToTestLeads)
+	}
+	if config.SubmittedAtStart != nil || config.SubmittedAtEnd != nil {
+		var timeRange []string
+		if config.SubmittedAtStart != nil {
+			timeRange = append(timeRange, fmt.Sprintf("start:%v", *config.SubmittedAtStart))
+		}
+		if config.SubmittedAtEnd != nil {
+			timeRange = append(timeRange, fmt.Sprintf("end:%v", *config.SubmittedAtEnd))
+		}
+		query += fmt.Sprintf("&submittedAtTimeRange=(%s)", strings.Join(timeRange, ","))
+	}
+
+	var leadFormResponses []LeadFormResponse
+
+	for {
+		var header = http.Header{}
+		header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+
+		var leadFormResponsesResponse LeadFormResponsesResponse
+
+		requestConfig := go_http.RequestConfig{
+			Method:            http.MethodGet,
+			Url:               service.urlRest(fmt.Sprintf("leadFormResponses?%s&start=%v&count=%v", query, start, count)),
+			ResponseModel:     &leadFormResponsesResponse,
+			NonDefaultHeaders: &header,
+		}
+		_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+		if e != nil {
+			return nil, e
+		}
+
+		leadFormResponses = append(leadFormResponses, leadFormResponsesResponse.Elements...)
+
+		if config.Start != nil {
+			break
+		}
+
+		if len(leadFormResponsesResponse.Elements) < int(count) {
+			break
+		}
+
+		start += count
+	}
+
+	return &leadFormResponses, nil
+}
+
+// SyncNewLeads returns the leads submitted since the previous committed run as CREATED changes,
+// the submittedAt of the newest lead is used as cursor
+func (syncer *Syncer) SyncNewLeads(owner LeadFormOwner, leadType LeadType) (*SyncResult, *errortools.Error) {
+	key := fmt.Sprintf("leads:%s:%s", owner.String(), leadType)
+
+	previous, e := syncer.stateStore.GetSyncState(key)
+	if e != nil {
+		return nil, e
+	}
+	if previous == nil {
+		previous = &SyncState{Entities: make(map[string]int64)}
+	}
+
+	pageSize := leadFormResponsesPageSize
+	config := GetLeadFormResponsesConfig{
+		Owner:    owner,
+		LeadType: leadType,
+		Count:    &pageSize,
+	}
+	if previous.HighWaterMark > 0 {
+		// inclusive, leads submitted in the same millisecond as the cursor are deduplicated below
+		config.SubmittedAtStart = &previous.HighWaterMark
+	}
+
+	leadFormResponses, e := syncer.service.GetLeadFormResponses(&config)
+	if e != nil {
+		return nil, e
+	}
+
+	var result = SyncResult{
+		key: key,
+		state: &SyncState{
+			HighWaterMark: previous.HighWaterMark,
+			Entities:      make(map[string]int64),
+		},
+		store: syncer.stateStore,
+	}
+
+	for i := range *leadFormResponses {
+		leadFormResponse := &(*leadFormResponses)[i]
+		if leadFormResponse.SubmittedAt < previous.HighWaterMark {
+			continue
+		}
+		if _, ok := previous.Entities[leadFormResponse.Id]; ok {
+			continue
+		}
+		if leadFormResponse.SubmittedAt > result.state.HighWaterMark {
+			result.state.HighWaterMark = leadFormResponse.SubmittedAt
+		}
+
+		result.Changes = append(result.Changes, SyncChange{
+			Type:             SyncChangeTypeCreated,
+			Urn:              leadFormResponse.Id,
+			LastModified:     leadFormResponse.SubmittedAt,
+			LeadFormResponse: leadFormResponse,
+		})
+	}
+
+	sort.SliceStable(result.Changes, func(i, j int) bool {
+		return result.Changes[i].LastModified < result.Changes[j].LastModified
+	})
+
+	// only the leads at the cursor are needed to deduplicate the next run
+	for urn, submittedAt := range previous.Entities {
+		if submittedAt == result.state.HighWaterMark {
+			result.state.Entities[urn] = submittedAt
+		}
+	}
+	for _, change := range result.Changes {
+		if change.LastModified == result.state.HighWaterMark {
+			result.state.Entities[change.Urn] = change.LastModified
+		}
+	}
+
+	return &result, nil
+}
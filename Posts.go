@@ -17,6 +17,7 @@ type Post struct {
 	Container                 string              `json:"container,omitempty"`
 	Content                   *PostContent        `json:"content,omitempty"`
 	ContentCallToActionLabel  string              `json:"contentCallToActionLabel,omitempty"`
+	ContentLandingPage        string              `json:"contentLandingPage,omitempty"`
 	CreatedAt                 int64               `json:"createdAt,omitempty"`
 	Distribution              PostDistribution    `json:"distribution,omitempty"`
 	Id                        string              `json:"id,omitempty"`
@@ -29,38 +30,40 @@ type Post struct {
 	Visibility                string              `json:"visibility,omitempty"`
 }
 
+type PostLifecycleState string
+
+const (
+	PostLifecycleStateDraft            PostLifecycleState = "DRAFT"
+	PostLifecycleStatePublished        PostLifecycleState = "PUBLISHED"
+	PostLifecycleStatePublishRequested PostLifecycleState = "PUBLISH_REQUESTED"
+	PostLifecycleStatePublishFailed    PostLifecycleState = "PUBLISH_FAILED"
+)
+
 type PostAdContext struct {
-	DscStatus    string `json:"dscStatus"`
+	DscStatus    string `json:"dscStatus,omitempty"`
 	DscAdType    string `json:"dscAdType"`
-	IsDsc        bool   `json:"isDsc"`
+	IsDsc        bool   `json:"isDsc,omitempty"`
 	DscAdAccount string `json:"dscAdAccount"`
+	DscName      string `json:"dscName,omitempty"`
 }
 
+type DscAdType string
+
+const (
+	DscAdTypeStandard       DscAdType = "STANDARD"
+	DscAdTypeVideo          DscAdType = "VIDEO"
+	DscAdTypeCarousel       DscAdType = "CAROUSEL"
+	DscAdTypeJobPosting     DscAdType = "JOB_POSTING"
+	DscAdTypeNativeDocument DscAdType = "NATIVE_DOCUMENT"
+	DscAdTypeEvent          DscAdType = "EVENT"
+)
+
 type PostDistribution struct {
 	FeedDistribution               string               `json:"feedDistribution"`
 	TargetEntities                 []DistributionTarget `json:"targetEntities,omitempty"`
 	ThirdPartyDistributionChannels []string             `json:"thirdPartyDistributionChannels,omitempty"`
 }
 
-type PostContent struct {
-	Media      *PostContentMedia      `json:"media,omitempty"`
-	MultiImage *PostContentMultiImage `json:"multiImage,omitempty"`
-}
-
-type PostContentMedia struct {
-	Title string `json:"title"`
-	Id    string `json:"id"`
-}
-
-type PostContentMultiImage struct {
-	Images []PostContentMultiImageImage `json:"images"`
-}
-
-type PostContentMultiImageImage struct {
-	Id      string `json:"id"`
-	AltText string `json:"altText"`
-}
-
 type LifecycleStateInfo struct {
 	IsEditedByAuthor bool `json:"isEditedByAuthor"`
 }
@@ -94,6 +97,15 @@ func (service *Service) CreatePost(post *Post) (string, *errortools.Error) {
 	if post == nil {
 		return "", errortools.ErrorMessage("Post pointer is nil")
 	}
+	if post.Content != nil {
+		e := post.Content.Validate()
+		if e != nil {
+			return "", e
+		}
+		if post.Content.Carousel != nil && post.AdContext == nil {
+			return "", errortools.ErrorMessage("Carousel content is only allowed in sponsored posts")
+		}
+	}
 
 	requestConfig := go_http.RequestConfig{
 		Method:    http.MethodPost,
@@ -113,13 +125,129 @@ func (service *Service) CreatePost(post *Post) (string, *errortools.Error) {
 	return postId, nil
 }
 
+// postUrl returns the url of a single post, urn can be either a urn:li:share or a urn:li:ugcPost
+func (service *Service) postUrl(urn string) string {
+	return service.urlRest(fmt.Sprintf("posts/%s", url.QueryEscape(urn)))
+}
+
+func (service *Service) GetPost(urn string) (*Post, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	var post Post
+
+	requestConfig := go_http.RequestConfig{
+		Method:        http.MethodGet,
+		Url:           service.postUrl(urn),
+		ResponseModel: &post,
+	}
+	_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+	if e != nil {
+		return nil, e
+	}
+
+	return &post, nil
+}
+
+type UpdatePostConfig struct {
+	Commentary               *string
+	ContentCallToActionLabel *string
+	LifecycleState           *PostLifecycleState
+}
+
+// UpdatePost partially updates a post, only the fields that are set in cfg are changed
+func (service *Service) UpdatePost(urn string, cfg *UpdatePostConfig) *errortools.Error {
+	if service == nil {
+		return errortools.ErrorMessage("Service pointer is nil")
+	}
+	if cfg == nil {
+		return errortools.ErrorMessage("UpdatePostConfig pointer is nil")
+	}
+
+	var set = make(map[string]interface{})
+	if cfg.Commentary != nil {
+		set["commentary"] = *cfg.Commentary
+	}
+	if cfg.ContentCallToActionLabel != nil {
+		set["contentCallToActionLabel"] = *cfg.ContentCallToActionLabel
+	}
+	if cfg.LifecycleState != nil {
+		set["lifecycleState"] = *cfg.LifecycleState
+	}
+	if len(set) == 0 {
+		return errortools.ErrorMessage("UpdatePostConfig contains no fields to update")
+	}
+
+	var header = http.Header{}
+	header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+	header.Set("X-RestLi-Method", "PARTIAL_UPDATE")
+
+	requestConfig := go_http.RequestConfig{
+		Method: http.MethodPost,
+		Url:    service.postUrl(urn),
+		BodyModel: map[string]interface{}{
+			"patch": map[string]interface{}{
+				"$set": set,
+			},
+		},
+		NonDefaultHeaders: &header,
+	}
+	_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+
+	return e
+}
+
+func (service *Service) DeletePost(urn string) *errortools.Error {
+	if service == nil {
+		return errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	var header = http.Header{}
+	header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+	header.Set("X-RestLi-Method", "DELETE")
+
+	requestConfig := go_http.RequestConfig{
+		Method:            http.MethodDelete,
+		Url:               service.postUrl(urn),
+		NonDefaultHeaders: &header,
+	}
+	_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+
+	return e
+}
+
+type PostVisibility string
+
+const (
+	PostVisibilityPublic      PostVisibility = "PUBLIC"
+	PostVisibilityConnections PostVisibility = "CONNECTIONS"
+	PostVisibilityLoggedIn    PostVisibility = "LOGGED_IN"
+	PostVisibilityContainer   PostVisibility = "CONTAINER"
+)
+
+type PostsSortBy string
+
+const (
+	PostsSortByLastModified PostsSortBy = "LAST_MODIFIED"
+	PostsSortByCreated      PostsSortBy = "CREATED"
+)
+
 type PostsByOwnerConfig struct {
-	OrganizationId         int64
+	OrganizationId int64
+	// Author overrules OrganizationId and can be either an organization (urn:li:organization) or member (urn:li:person) urn
+	Author                 *string
 	Fields                 *string
+	SortBy                 *PostsSortBy // default LAST_MODIFIED
+	IsDsc                  *bool
+	LifecycleStates        *[]PostLifecycleState
+	Visibilities           *[]PostVisibility
 	CreatedStartDateUnix   *int64
 	CreatedEndDateUnix     *int64
 	PublishedStartDateUnix *int64
 	PublishedEndDateUnix   *int64
+	// LastModifiedStartDateUnix skips posts last modified before it, paging stops once it is passed when sorted by LAST_MODIFIED
+	LastModifiedStartDateUnix *int64
 }
 
 type PostsByOwnerResponse struct {
@@ -127,6 +255,12 @@ type PostsByOwnerResponse struct {
 	Elements []Post `json:"elements"`
 }
 
+// PostsByOwner returns the posts of an author, newest first
+//
+// LifecycleStates, Visibilities and the date bounds are filtered client-side, all posts are requested from the API.
+// Paging stops as soon as the sort order guarantees no further posts can match CreatedStartDateUnix, PublishedStartDateUnix
+// or LastModifiedStartDateUnix.
+// If Fields is passed, the fields needed for the filters and for paging are added to it.
 func (service *Service) PostsByOwner(cfg *PostsByOwnerConfig) (*[]Post, *errortools.Error) {
 	if service == nil {
 		return nil, errortools.ErrorMessage("Service pointer is nil")
@@ -138,31 +272,108 @@ func (service *Service) PostsByOwner(cfg *PostsByOwnerConfig) (*[]Post, *errorto
 	start := 0
 	count := 50
 
+	author := fmt.Sprintf("%s%v", OrganizationUrnPrefix, cfg.OrganizationId)
+	if cfg.Author != nil {
+		author = *cfg.Author
+	}
+	sortBy := PostsSortByLastModified
+	if cfg.SortBy != nil {
+		sortBy = *cfg.SortBy
+	}
+
+	var lifecycleStates = make(map[string]bool)
+	if cfg.LifecycleStates != nil {
+		for _, lifecycleState := range *cfg.LifecycleStates {
+			lifecycleStates[string(lifecycleState)] = true
+		}
+	}
+	var visibilities = make(map[string]bool)
+	if cfg.Visibilities != nil {
+		for _, visibility := range *cfg.Visibilities {
+			visibilities[string(visibility)] = true
+		}
+	}
+
+	// beyondStart reports whether post and all posts that follow it in the sort order are older than the start bounds
+	var beyondStart = func(post *Post) bool {
+		switch sortBy {
+		case PostsSortByCreated:
+			return cfg.CreatedStartDateUnix != nil && post.CreatedAt < *cfg.CreatedStartDateUnix
+		case PostsSortByLastModified:
+			// createdAt and publishedAt never exceed lastModifiedAt
+			if post.LastModifiedAt == 0 {
+				return false
+			}
+			if cfg.CreatedStartDateUnix != nil && post.LastModifiedAt < *cfg.CreatedStartDateUnix {
+				return true
+			}
+			if cfg.PublishedStartDateUnix != nil && post.LastModifiedAt < *cfg.PublishedStartDateUnix {
+				return true
+			}
+			if cfg.LastModifiedStartDateUnix != nil && post.LastModifiedAt < *cfg.LastModifiedStartDateUnix {
+				return true
+			}
+		}
+		return false
+	}
+
+	var fields string
+	if cfg.Fields != nil {
+		var required []string
+		if cfg.CreatedStartDateUnix != nil || cfg.CreatedEndDateUnix != nil || cfg.PublishedStartDateUnix != nil || cfg.PublishedEndDateUnix != nil || cfg.LastModifiedStartDateUnix != nil {
+			required = append(required, "createdAt", "lastModifiedAt")
+		}
+		if cfg.PublishedStartDateUnix != nil || cfg.PublishedEndDateUnix != nil {
+			required = append(required, "publishedAt")
+		}
+		if len(lifecycleStates) > 0 {
+			required = append(required, "lifecycleState")
+		}
+		if len(visibilities) > 0 {
+			required = append(required, "visibility")
+		}
+		fields = addProjectionFields(*cfg.Fields, required)
+	}
+
 	var posts []Post
 
 	for {
 		values := url.Values{}
 		values.Set("q", "author")
-		values.Set("author", fmt.Sprintf("urn:li:organization:%v", cfg.OrganizationId))
-		if cfg.Fields != nil {
-			values.Set("fields", *cfg.Fields)
+		values.Set("author", author)
+		values.Set("sortBy", string(sortBy))
+		if cfg.IsDsc != nil {
+			values.Set("isDsc", fmt.Sprintf("%v", *cfg.IsDsc))
+		}
+		if fields != "" {
+			values.Set("fields", fields)
 		}
 		values.Set("start", strconv.Itoa(start))
 		values.Set("count", strconv.Itoa(count))
 
 		postsResponse := PostsByOwnerResponse{}
 
+		var header = http.Header{}
+		header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+
 		requestConfig := go_http.RequestConfig{
-			Method:        http.MethodGet,
-			Url:           service.urlRest(fmt.Sprintf("posts?%s", values.Encode())),
-			ResponseModel: &postsResponse,
+			Method:            http.MethodGet,
+			Url:               service.urlRest(fmt.Sprintf("posts?%s", values.Encode())),
+			ResponseModel:     &postsResponse,
+			NonDefaultHeaders: &header,
 		}
 		_, _, e := service.versionedHttpRequest(&requestConfig, nil)
 		if e != nil {
 			return nil, e
 		}
 
+		done := false
+
 		for _, post := range postsResponse.Elements {
+			if beyondStart(&post) {
+				done = true
+				break
+			}
 
 			if cfg.CreatedEndDateUnix != nil {
 				if post.CreatedAt > *cfg.CreatedEndDateUnix {
@@ -188,10 +399,24 @@ func (service *Service) PostsByOwner(cfg *PostsByOwnerConfig) (*[]Post, *errorto
 				}
 			}
 
+			if cfg.LastModifiedStartDateUnix != nil {
+				if post.LastModifiedAt < *cfg.LastModifiedStartDateUnix {
+					continue
+				}
+			}
+
+			if len(lifecycleStates) > 0 && !lifecycleStates[post.LifecycleState] {
+				continue
+			}
+
+			if len(visibilities) > 0 && !visibilities[post.Visibility] {
+				continue
+			}
+
 			posts = append(posts, post)
 		}
 
-		if !postsResponse.Paging.HasLink("next") {
+		if done || !postsResponse.Paging.HasLink("next") {
 			break
 		}
 
@@ -201,6 +426,52 @@ func (service *Service) PostsByOwner(cfg *PostsByOwnerConfig) (*[]Post, *errorto
 	return &posts, nil
 }
 
+// addProjectionFields adds the required top level fields to a Rest.li field projection if it lacks them
+func addProjectionFields(fields string, required []string) string {
+	fields = strings.TrimRight(strings.TrimSpace(fields), ", ")
+
+	var present = make(map[string]bool)
+	depth := 0
+	start := 0
+	for i := 0; i <= len(fields); i++ {
+		if i < len(fields) {
+			switch fields[i] {
+			case '(':
+				depth++
+				continue
+			case ')':
+				depth--
+				continue
+			case ',':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		field := strings.TrimSpace(fields[start:i])
+		if j := strings.IndexAny(field, ":("); j >= 0 {
+			field = strings.TrimSpace(field[:j])
+		}
+		present[field] = true
+		start = i + 1
+	}
+
+	for _, field := range required {
+		if present[field] {
+			continue
+		}
+		if fields != "" {
+			fields += ","
+		}
+		fields += field
+		present[field] = true
+	}
+
+	return fields
+}
+
 type PostsResponse struct {
 	Results map[string]Post `json:"results"`
 }
@@ -0,0 +1,35 @@
+package linkedin
+
+import "testing"
+
+func TestAddProjectionFields(t *testing.T) {
+	required := []string{"createdAt", "lastModifiedAt"}
+
+	tests := []struct {
+		name     string
+		fields   string
+		required []string
+		expected string
+	}{
+		{"empty", "", required, "createdAt,lastModifiedAt"},
+		{"nothing required", "id,commentary", nil, "id,commentary"},
+		{"appends missing", "id", required, "id,createdAt,lastModifiedAt"},
+		{"already present", "createdAt,id,lastModifiedAt", required, "createdAt,id,lastModifiedAt"},
+		{"partially present", "id,lastModifiedAt", required, "id,lastModifiedAt,createdAt"},
+		{"nested", "id,content(media(id))", required, "id,content(media(id)),createdAt,lastModifiedAt"},
+		{"nested with colons", "id,content:(media:(id,title))", required, "id,content:(media:(id,title)),createdAt,lastModifiedAt"},
+		{"only nested occurrence", "content:(media:(createdAt))", []string{"createdAt"}, "content:(media:(createdAt)),createdAt"},
+		{"nested then present", "content(media(id)),createdAt", []string{"createdAt"}, "content(media(id)),createdAt"},
+		{"trailing comma", "id,", required, "id,createdAt,lastModifiedAt"},
+		{"trailing commas and spaces", "id, createdAt , ", required, "id, createdAt,lastModifiedAt"},
+		{"duplicate required", "id", []string{"createdAt", "createdAt"}, "id,createdAt"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := addProjectionFields(test.fields, test.required); actual != test.expected {
+				t.Errorf("addProjectionFields(%q) = %q, expected %q", test.fields, actual, test.expected)
+			}
+		})
+	}
+}
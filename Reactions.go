@@ -0,0 +1,158 @@
+package linkedin
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	errortools "github.com/leapforce-libraries/go_errortools"
+	go_http "github.com/leapforce-libraries/go_http"
+)
+
+type ReactionsResponse struct {
+	Paging   Paging     `json:"paging"`
+	Elements []Reaction `json:"elements"`
+}
+
+type Reaction struct {
+	Id           string           `json:"id,omitempty"`
+	Root         string           `json:"root"`
+	ReactionType ReactionType     `json:"reactionType"`
+	Created      *CreatedModified `json:"created,omitempty"`
+	LastModified *CreatedModified `json:"lastModified,omitempty"`
+}
+
+type ReactionType string
+
+const (
+	ReactionTypeLike          ReactionType = "LIKE"
+	ReactionTypePraise        ReactionType = "PRAISE"
+	ReactionTypeEmpathy       ReactionType = "EMPATHY"
+	ReactionTypeInterest      ReactionType = "INTEREST"
+	ReactionTypeAppreciation  ReactionType = "APPRECIATION"
+	ReactionTypeEntertainment ReactionType = "ENTERTAINMENT"
+)
+
+// Actor returns the urn of the member or organization that reacted
+func (reaction *Reaction) Actor() string {
+	if reaction.Created == nil {
+		return ""
+	}
+
+	return reaction.Created.Actor
+}
+
+// CreateReaction reacts on a post (urn:li:share, urn:li:ugcPost) or comment (urn:li:comment) as actor,
+// actor being a member (urn:li:person) or organization (urn:li:organization) urn
+func (service *Service) CreateReaction(actor string, entity string, reactionType ReactionType) (*Reaction, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	var header = http.Header{}
+	header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+
+	var reaction Reaction
+
+	requestConfig := go_http.RequestConfig{
+		Method: http.MethodPost,
+		Url:    service.urlRest(fmt.Sprintf("reactions?actor=%s", url.QueryEscape(actor))),
+		BodyModel: Reaction{
+			Root:         entity,
+			ReactionType: reactionType,
+		},
+		ResponseModel:     &reaction,
+		NonDefaultHeaders: &header,
+	}
+	_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+	if e != nil {
+		return nil, e
+	}
+
+	return &reaction, nil
+}
+
+func (service *Service) DeleteReaction(actor string, entity string) *errortools.Error {
+	if service == nil {
+		return errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	var header = http.Header{}
+	header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+	header.Set("X-RestLi-Method", "DELETE")
+
+	requestConfig := go_http.RequestConfig{
+		Method:            http.MethodDelete,
+		Url:               service.urlRest(fmt.Sprintf("reactions/(actor:%s,entity:%s)", url.QueryEscape(actor), url.QueryEscape(entity))),
+		NonDefaultHeaders: &header,
+	}
+	_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+
+	return e
+}
+
+type GetReactionsConfig struct {
+	Entity string
+	Start  *uint
+	Count  *uint
+}
+
+// GetReactions returns the reactions on a post or comment, most recent first
+func (service *Service) GetReactions(config *GetReactionsConfig) (*[]Reaction, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+	if config == nil {
+		return nil, errortools.ErrorMessage("GetReactionsConfig must not be nil")
+	}
+
+	var start uint = 0
+	var count uint = countDefault
+
+	if config.Start != nil {
+		start = *config.Start
+	}
+	if config.Count != nil {
+		count = *config.Count
+	}
+
+	var values = url.Values{}
+	values.Set("q", "entity")
+	values.Set("count", fmt.Sprintf("%v", count))
+
+	var reactions []Reaction
+
+	for {
+		values.Set("start", fmt.Sprintf("%v", start))
+
+		var header = http.Header{}
+		header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+
+		var reactionsResponse ReactionsResponse
+
+		requestConfig := go_http.RequestConfig{
+			Method:            http.MethodGet,
+			Url:               service.urlRest(fmt.Sprintf("reactions/(entity:%s)?sort=(value:REVERSE_CHRONOLOGICAL)&%s", url.QueryEscape(config.Entity), values.Encode())),
+			ResponseModel:     &reactionsResponse,
+			NonDefaultHeaders: &header,
+		}
+		_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+		if e != nil {
+			return nil, e
+		}
+
+		reactions = append(reactions, reactionsResponse.Elements...)
+
+		if config.Start != nil {
+			break
+		}
+
+		if len(reactionsResponse.Elements) < int(count) {
+			break
+		}
+
+		start += count
+	}
+
+	return &reactions, nil
+}
@@ -8,34 +8,43 @@ import (
 	go_token "github.com/leapforce-libraries/go_oauth2/token"
 	tokensource "github.com/leapforce-libraries/go_oauth2/tokensource"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 )
 
 const (
-	apiName                      string = "LinkedIn"
-	apiUrlRest                   string = "https://api.linkedin.com/rest"
-	apiUrl                       string = "https://api.linkedin.com"
-	oauthUrl                     string = "https://www.linkedin.com/oauth/v2"
-	authUrl                      string = "https://www.linkedin.com/oauth/v2/authorization"
-	tokenUrl                     string = "https://www.linkedin.com/oauth/v2/accessToken"
-	linkedInVersionHeader        string = "LinkedIn-Version"
-	restliProtocolVersionHeader  string = "X-Restli-Protocol-Version"
-	defaultRestliProtocolVersion string = "2.0.0"
-	tokenHttpMethod              string = http.MethodPost
-	defaultRedirectUrl           string = "http://localhost:8080/oauth/redirect"
-	AccountUrnPrefix             string = "urn:li:sponsoredAccount:"
-	CampaignUrnPrefix            string = "urn:li:sponsoredCampaign:"
-	CreativeUrnPrefix            string = "urn:li:sponsoredCreative:"
-	InMailContentUrnPrefix       string = "urn:li:adInMailContent:"
-	OrganizationUrnPrefix        string = "urn:li:organization:"
-	ShareUrnPrefix               string = "urn:li:share:"
-	UgcPostUrnPrefix             string = "urn:li:ugcPost:"
-	PostUrnPrefix                string = "urn:li:post:"
-	GeoUrnPrefix                 string = "urn:li:geo:"
-	ConversionUrnPrefix          string = "urn:lla:llaPartnerConversion:"
-	countDefault                 uint   = 10
-	maxUrnsPerCall               uint   = 50
+	apiName                       string = "LinkedIn"
+	apiUrlRest                    string = "https://api.linkedin.com/rest"
+	apiUrl                        string = "https://api.linkedin.com"
+	oauthUrl                      string = "https://www.linkedin.com/oauth/v2"
+	authUrl                       string = "https://www.linkedin.com/oauth/v2/authorization"
+	tokenUrl                      string = "https://www.linkedin.com/oauth/v2/accessToken"
+	linkedInVersionHeader         string = "LinkedIn-Version"
+	restliProtocolVersionHeader   string = "X-Restli-Protocol-Version"
+	defaultRestliProtocolVersion  string = "2.0.0"
+	tokenHttpMethod               string = http.MethodPost
+	defaultRedirectUrl            string = "http://localhost:8080/oauth/redirect"
+	AccountUrnPrefix              string = "urn:li:sponsoredAccount:"
+	CampaignUrnPrefix             string = "urn:li:sponsoredCampaign:"
+	CampaignGroupUrnPrefix        string = "urn:li:sponsoredCampaignGroup:"
+	CreativeUrnPrefix             string = "urn:li:sponsoredCreative:"
+	InMailContentUrnPrefix        string = "urn:li:adInMailContent:"
+	OrganizationUrnPrefix         string = "urn:li:organization:"
+	ShareUrnPrefix                string = "urn:li:share:"
+	UgcPostUrnPrefix              string = "urn:li:ugcPost:"
+	PostUrnPrefix                 string = "urn:li:post:"
+	GeoUrnPrefix                  string = "urn:li:geo:"
+	ConversionUrnPrefix           string = "urn:lla:llaPartnerConversion:"
+	DocumentUrnPrefix             string = "urn:li:document:"
+	ImageUrnPrefix                string = "urn:li:image:"
+	DigitalmediaAssetUrnPrefix    string = "urn:li:digitalmediaAsset:"
+	DeveloperApplicationUrnPrefix string = "urn:li:developerApplication:"
+	LeadGenFormUrnPrefix          string = "urn:li:leadGenForm:"
+	PersonUrnPrefix               string = "urn:li:person:"
+	VideoUrnPrefix                string = "urn:li:video:"
+	countDefault                  uint   = 10
+	maxUrnsPerCall                uint   = 50
 )
 
 type Service struct {
@@ -123,6 +132,55 @@ func (service *Service) urlRest(path string) string {
 	return fmt.Sprintf("%s/%s", apiUrlRest, path)
 }
 
+// urnBatches deduplicates urns and splits them into batches of at most maxUrnsPerCall, keeping their order
+func urnBatches(urns []string) [][]string {
+	var _urnsMap = make(map[string]bool)
+	var _urns []string
+	for _, urn := range urns {
+		_, ok := _urnsMap[urn]
+		if ok {
+			continue
+		}
+		_urnsMap[urn] = true
+		_urns = append(_urns, urn)
+	}
+
+	var batches [][]string
+	for len(_urns) > 0 {
+		if len(_urns) > int(maxUrnsPerCall) {
+			batches = append(batches, _urns[:maxUrnsPerCall])
+			_urns = _urns[maxUrnsPerCall:]
+		} else {
+			batches = append(batches, _urns)
+			_urns = []string{}
+		}
+	}
+
+	return batches
+}
+
+// batchGet requests a batch of urns from resource with the Rest.li BATCH_GET method
+func (service *Service) batchGet(resource string, urnsBatch []string, responseModel interface{}) *errortools.Error {
+	var ids []string
+	for _, urn := range urnsBatch {
+		ids = append(ids, url.QueryEscape(urn))
+	}
+
+	var header = http.Header{}
+	header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+	header.Set("X-RestLi-Method", "BATCH_GET")
+
+	requestConfig := go_http.RequestConfig{
+		Method:            http.MethodGet,
+		Url:               service.urlRest(fmt.Sprintf("%s?ids=List(%s)", resource, strings.Join(ids, ","))),
+		ResponseModel:     responseModel,
+		NonDefaultHeaders: &header,
+	}
+	_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+
+	return e
+}
+
 func (service *Service) urlOAuth(path string) string {
 	return fmt.Sprintf("%s/%s", oauthUrl, path)
 }
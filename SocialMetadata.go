@@ -0,0 +1,112 @@
+package linkedin
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	errortools "github.com/leapforce-libraries/go_errortools"
+	go_http "github.com/leapforce-libraries/go_http"
+)
+
+type SocialMetadataResponse struct {
+	Results map[string]SocialMetadata `json:"results"`
+}
+
+type SocialMetadata struct {
+	Entity            string                           `json:"entity"`
+	ReactionSummaries map[ReactionType]ReactionSummary `json:"reactionSummaries"`
+	CommentSummary    CommentSummary                   `json:"commentSummary"`
+	CommentsState     CommentsState                    `json:"commentsState"`
+}
+
+type ReactionSummary struct {
+	ReactionType ReactionType `json:"reactionType"`
+	Count        int64        `json:"count"`
+}
+
+type CommentSummary struct {
+	Count         int64 `json:"count"`
+	TopLevelCount int64 `json:"topLevelCount"`
+}
+
+type CommentsState string
+
+const (
+	CommentsStateOpen   CommentsState = "OPEN"
+	CommentsStateClosed CommentsState = "CLOSED"
+)
+
+// TotalReactions returns the sum of the reactions of all types
+func (socialMetadata *SocialMetadata) TotalReactions() int64 {
+	var total int64
+	for _, reactionSummary := range socialMetadata.ReactionSummaries {
+		total += reactionSummary.Count
+	}
+
+	return total
+}
+
+func (socialMetadata *SocialMetadata) CommentsEnabled() bool {
+	return socialMetadata.CommentsState != CommentsStateClosed
+}
+
+// GetSocialMetadata returns reaction and comment summaries for posts and comments,
+// unlike GetShareStatsLifetime it does not require the posts to be owned by an organization
+func (service *Service) GetSocialMetadata(urns []string) (*[]SocialMetadata, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	var socialMetadatas []SocialMetadata
+
+	for _, _urnsBatch := range urnBatches(urns) {
+		socialMetadataResponse := SocialMetadataResponse{}
+
+		e := service.batchGet("socialMetadata", _urnsBatch, &socialMetadataResponse)
+		if e != nil {
+			return nil, e
+		}
+
+		// results are returned in the order of the urns
+		for _, urn := range _urnsBatch {
+			socialMetadata, ok := socialMetadataResponse.Results[urn]
+			if !ok {
+				continue
+			}
+			if socialMetadata.Entity == "" {
+				socialMetadata.Entity = urn
+			}
+			socialMetadatas = append(socialMetadatas, socialMetadata)
+		}
+	}
+
+	return &socialMetadatas, nil
+}
+
+// SetCommentsState enables (OPEN) or disables (CLOSED) comments on a post, actor being the author of the post
+func (service *Service) SetCommentsState(actor string, urn string, commentsState CommentsState) *errortools.Error {
+	if service == nil {
+		return errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	var header = http.Header{}
+	header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+	header.Set("X-RestLi-Method", "PARTIAL_UPDATE")
+
+	requestConfig := go_http.RequestConfig{
+		Method: http.MethodPost,
+		Url:    service.urlRest(fmt.Sprintf("socialMetadata/%s?actor=%s", url.QueryEscape(urn), url.QueryEscape(actor))),
+		BodyModel: map[string]interface{}{
+			"patch": map[string]interface{}{
+				"$set": map[string]interface{}{
+					"commentsState": commentsState,
+				},
+			},
+		},
+		NonDefaultHeaders: &header,
+	}
+	_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+
+	return e
+}
@@ -1,10 +1,13 @@
 package linkedin
 
 import (
+	"fmt"
 	errortools "github.com/leapforce-libraries/go_errortools"
 	go_http "github.com/leapforce-libraries/go_http"
 	"io"
 	"net/http"
+	"net/url"
+	"time"
 )
 
 type InitializeUploadVideoRequest struct {
@@ -20,6 +23,8 @@ type InitializeUploadVideoResponse struct {
 		Video               string                             `json:"video"`
 		UploadInstructions  []InitializeUploadVideoInstruction `json:"uploadInstructions"`
 		UploadToken         string                             `json:"uploadToken"`
+		CaptionsUploadUrl   string                             `json:"captionsUploadUrl,omitempty"`
+		ThumbnailUploadUrl  string                             `json:"thumbnailUploadUrl,omitempty"`
 	} `json:"value"`
 }
 
@@ -131,3 +136,197 @@ func (service *Service) FinalizeUploadVideo(finalizeUploadVideoRequest *Finalize
 
 	return e
 }
+
+type Video struct {
+	Owner                   string      `json:"owner"`
+	Status                  MediaStatus `json:"status"`
+	Id                      string      `json:"id"`
+	DownloadUrl             string      `json:"downloadUrl,omitempty"`
+	DownloadUrlExpiresAt    int64       `json:"downloadUrlExpiresAt,omitempty"`
+	Duration                int64       `json:"duration,omitempty"` // milliseconds
+	AspectRatioWidth        float64     `json:"aspectRatioWidth,omitempty"`
+	AspectRatioHeight       float64     `json:"aspectRatioHeight,omitempty"`
+	Thumbnail               string      `json:"thumbnail,omitempty"`
+	ProcessingFailureReason string      `json:"processingFailureReason,omitempty"`
+}
+
+// DownloadUrlExpiresAtTime returns the time at which DownloadUrl expires
+func (video *Video) DownloadUrlExpiresAtTime() time.Time {
+	return time.UnixMilli(video.DownloadUrlExpiresAt)
+}
+
+func (video *Video) DurationTime() time.Duration {
+	return time.Duration(video.Duration) * time.Millisecond
+}
+
+// AspectRatio returns the aspect ratio of the video, nil if it is not known yet
+func (video *Video) AspectRatio() *AspectRatio {
+	if video.AspectRatioWidth == 0 || video.AspectRatioHeight == 0 {
+		return nil
+	}
+
+	return &AspectRatio{
+		HeightAspect: video.AspectRatioHeight,
+		WidthAspect:  video.AspectRatioWidth,
+	}
+}
+
+func (service *Service) GetVideo(videoUrn string) (*Video, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	var header = http.Header{}
+	header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+
+	var video Video
+
+	requestConfig := go_http.RequestConfig{
+		Method:            http.MethodGet,
+		Url:               service.urlRest(fmt.Sprintf("videos/%s", url.QueryEscape(videoUrn))),
+		ResponseModel:     &video,
+		NonDefaultHeaders: &header,
+	}
+	_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+	if e != nil {
+		return nil, e
+	}
+
+	return &video, nil
+}
+
+// WaitForVideo polls the status of the video until it is AVAILABLE
+func (service *Service) WaitForVideo(videoUrn string, config *WaitForMediaConfig) (*Video, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	var video *Video
+
+	e := waitForMedia(videoUrn, config, func() (MediaStatus, *errortools.Error) {
+		var e *errortools.Error
+		video, e = service.GetVideo(videoUrn)
+		if e != nil {
+			return "", e
+		}
+		return video.Status, nil
+	})
+	if e != nil {
+		if video != nil && video.ProcessingFailureReason != "" {
+			e.SetMessage(fmt.Sprintf("Processing of %s failed: %s", videoUrn, video.ProcessingFailureReason))
+		}
+		return nil, e
+	}
+
+	return video, nil
+}
+
+type UploadVideoAndWaitConfig struct {
+	StreamUploadVideoConfig
+	Wait *WaitForMediaConfig
+}
+
+// UploadVideoAndWait initializes the upload, uploads and finalizes the video and waits until it is AVAILABLE
+func (service *Service) UploadVideoAndWait(config *UploadVideoAndWaitConfig) (*Video, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+	if config == nil {
+		return nil, errortools.ErrorMessage("UploadVideoAndWaitConfig must not be a nil pointer")
+	}
+
+	session, e := service.StreamUploadVideo(&config.StreamUploadVideoConfig)
+	if e != nil {
+		return nil, e
+	}
+
+	return service.WaitForVideo(session.Video, config.Wait)
+}
+
+type VideosResponse struct {
+	Results map[string]Video `json:"results"`
+}
+
+// BatchGetVideos returns the videos for the given urns in their order, requested in batches of at most 50
+func (service *Service) BatchGetVideos(videoUrns []string) (*[]Video, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	var videos []Video
+
+	for _, _urnsBatch := range urnBatches(videoUrns) {
+		videosResponse := VideosResponse{}
+
+		e := service.batchGet("videos", _urnsBatch, &videosResponse)
+		if e != nil {
+			return nil, e
+		}
+
+		// results are returned in the order of the urns
+		for _, urn := range _urnsBatch {
+			_video, ok := videosResponse.Results[urn]
+			if !ok {
+				continue
+			}
+			if _video.Id == "" {
+				_video.Id = urn
+			}
+			videos = append(videos, _video)
+		}
+	}
+
+	return &videos, nil
+}
+
+type VideosByOwnerResponse struct {
+	Paging   Paging  `json:"paging"`
+	Elements []Video `json:"elements"`
+}
+
+// GetVideosByOwner returns all videos owned by owner, e.g. an organization (urn:li:organization) or member (urn:li:person)
+func (service *Service) GetVideosByOwner(owner string) (*[]Video, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+
+	var start uint = 0
+	var count uint = countDefault
+
+	var values = url.Values{}
+	values.Set("q", "owner")
+	values.Set("owner", owner)
+	values.Set("count", fmt.Sprintf("%v", count))
+
+	var videos []Video
+
+	for {
+		values.Set("start", fmt.Sprintf("%v", start))
+
+		var header = http.Header{}
+		header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+
+		var videosByOwnerResponse VideosByOwnerResponse
+
+		requestConfig := go_http.RequestConfig{
+			Method:            http.MethodGet,
+			Url:               service.urlRest(fmt.Sprintf("videos?%s", values.Encode())),
+			ResponseModel:     &videosByOwnerResponse,
+			NonDefaultHeaders: &header,
+		}
+		_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+		if e != nil {
+			return nil, e
+		}
+
+		videos = append(videos, videosByOwnerResponse.Elements...)
+
+		if len(videosByOwnerResponse.Elements) < int(count) {
+			break
+		}
+
+		start += count
+	}
+
+	return &videos, nil
+}
@@ -0,0 +1,300 @@
+package linkedin
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	errortools "github.com/leapforce-libraries/go_errortools"
+	go_http "github.com/leapforce-libraries/go_http"
+)
+
+const (
+	webhookSignatureHeader            string = "X-LI-Signature"
+	webhookSignaturePrefix            string = "hmacsha256="
+	webhookMaxBodySize                int64  = 1 << 20
+	organizationSocialActionEventType string = "ORGANIZATION_SOCIAL_ACTION_NOTIFICATIONS"
+)
+
+type SocialActionType string
+
+const (
+	SocialActionTypeComment          SocialActionType = "COMMENT"
+	SocialActionTypeCommentEdit      SocialActionType = "COMMENT_EDIT"
+	SocialActionTypeCommentDelete    SocialActionType = "COMMENT_DELETE"
+	SocialActionTypeAdminComment     SocialActionType = "ADMIN_COMMENT"
+	SocialActionTypeMentionInComment SocialActionType = "MENTION_IN_COMMENT"
+	SocialActionTypeShareMention     SocialActionType = "SHARE_MENTION"
+	SocialActionTypeShare            SocialActionType = "SHARE"
+	SocialActionTypeLike             SocialActionType = "LIKE"
+)
+
+type OrganizationSocialActionNotifications struct {
+	Type          string                                 `json:"type"`
+	Notifications []OrganizationSocialActionNotification `json:"notifications"`
+}
+
+type OrganizationSocialActionNotification struct {
+	NotificationId             int64                       `json:"notificationId"`
+	Action                     SocialActionType            `json:"action"`
+	OrganizationalEntity       string                      `json:"organizationalEntity"`
+	SourcePost                 string                      `json:"sourcePost"`
+	GeneratedActivity          string                      `json:"generatedActivity"`
+	Subscriber                 string                      `json:"subscriber"`
+	LastModifiedAt             int64                       `json:"lastModifiedAt"`
+	DecoratedSourcePost        *DecoratedSourcePost        `json:"decoratedSourcePost,omitempty"`
+	DecoratedGeneratedActivity *DecoratedGeneratedActivity `json:"decoratedGeneratedActivity,omitempty"`
+}
+
+type DecoratedSourcePost struct {
+	Entity string `json:"entity"`
+	Owner  string `json:"owner"`
+}
+
+// DecoratedGeneratedActivity holds the comment, share or reaction that triggered the notification
+type DecoratedGeneratedActivity struct {
+	Comment  *DecoratedComment  `json:"comment,omitempty"`
+	Share    *DecoratedShare    `json:"share,omitempty"`
+	Reaction *DecoratedReaction `json:"reaction,omitempty"`
+}
+
+type DecoratedComment struct {
+	Entity        string `json:"entity"`
+	Object        string `json:"object"`
+	Owner         string `json:"owner"`
+	Text          string `json:"text"`
+	ParentComment string `json:"parentComment,omitempty"`
+}
+
+type DecoratedShare struct {
+	Entity string `json:"entity"`
+	Owner  string `json:"owner"`
+	Text   string `json:"text"`
+}
+
+type DecoratedReaction struct {
+	Entity       string       `json:"entity"`
+	Owner        string       `json:"owner"`
+	ReactionType ReactionType `json:"reactionType"`
+}
+
+// SocialActionCallback handles a notification, returning an error makes LinkedIn retry the delivery
+type SocialActionCallback func(notification *OrganizationSocialActionNotification) *errortools.Error
+
+// WebhookHandler is an http.Handler that validates and dispatches LinkedIn webhook notifications
+type WebhookHandler struct {
+	clientSecret string
+	mutex        sync.RWMutex
+	callbacks    map[SocialActionType][]SocialActionCallback
+	fallback     []SocialActionCallback
+}
+
+// NewWebhookHandler returns a WebhookHandler that signs and verifies with the client secret of the service
+func (service *Service) NewWebhookHandler() (*WebhookHandler, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+	if service.clientSecret == "" {
+		return nil, errortools.ErrorMessage("Service has no client secret, webhook signatures cannot be verified")
+	}
+
+	return &WebhookHandler{
+		clientSecret: service.clientSecret,
+		callbacks:    make(map[SocialActionType][]SocialActionCallback),
+	}, nil
+}
+
+// On registers callback for notifications of the given action
+func (handler *WebhookHandler) On(action SocialActionType, callback SocialActionCallback) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	handler.callbacks[action] = append(handler.callbacks[action], callback)
+}
+
+// OnAny registers callback for notifications of all actions
+func (handler *WebhookHandler) OnAny(callback SocialActionCallback) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+
+	handler.fallback = append(handler.fallback, callback)
+}
+
+func (handler *WebhookHandler) sign(b []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(handler.clientSecret))
+	mac.Write(b)
+	return mac.Sum(nil)
+}
+
+func (handler *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		handler.serveChallenge(w, r)
+	case http.MethodPost:
+		handler.serveNotification(w, r)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// serveChallenge answers the challenge code LinkedIn sends when validating the webhook url
+func (handler *WebhookHandler) serveChallenge(w http.ResponseWriter, r *http.Request) {
+	challengeCode := r.URL.Query().Get("challengeCode")
+	if challengeCode == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	b, err := json.Marshal(struct {
+		ChallengeCode     string `json:"challengeCode"`
+		ChallengeResponse string `json:"challengeResponse"`
+	}{
+		challengeCode,
+		hex.EncodeToString(handler.sign([]byte(challengeCode))),
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(b)
+}
+
+func (handler *WebhookHandler) serveNotification(w http.ResponseWriter, r *http.Request) {
+	b, err := io.ReadAll(io.LimitReader(r.Body, webhookMaxBodySize+1))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if int64(len(b)) > webhookMaxBodySize {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if !handler.verify(b, r.Header.Get(webhookSignatureHeader)) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var notifications OrganizationSocialActionNotifications
+	err = json.Unmarshal(b, &notifications)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	for i := range notifications.Notifications {
+		e := handler.dispatch(&notifications.Notifications[i])
+		if e != nil {
+			errortools.CaptureError(e)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// verify checks the X-LI-Signature header against the HMAC-SHA256 of the body
+func (handler *WebhookHandler) verify(body []byte, signature string) bool {
+	signature = strings.TrimPrefix(strings.TrimSpace(signature), webhookSignaturePrefix)
+	if signature == "" || handler.clientSecret == "" {
+		return false
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal(expected, handler.sign(body))
+}
+
+func (handler *WebhookHandler) dispatch(notification *OrganizationSocialActionNotification) *errortools.Error {
+	handler.mutex.RLock()
+	callbacks := append(append([]SocialActionCallback{}, handler.callbacks[notification.Action]...), handler.fallback...)
+	handler.mutex.RUnlock()
+
+	for _, callback := range callbacks {
+		e := callback(notification)
+		if e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
+
+type WebhookSubscriptionConfig struct {
+	DeveloperApplicationId int64
+	PersonId               string // id of the member that administers the organization
+	OrganizationId         int64
+	WebhookUrl             string
+}
+
+func (service *Service) webhookSubscriptionUrl(cfg *WebhookSubscriptionConfig) string {
+	key := fmt.Sprintf("(developerApplication:%s,user:%s,entity:%s,eventType:%s)",
+		url.QueryEscape(fmt.Sprintf("%s%v", DeveloperApplicationUrnPrefix, cfg.DeveloperApplicationId)),
+		url.QueryEscape(fmt.Sprintf("%s%s", PersonUrnPrefix, cfg.PersonId)),
+		url.QueryEscape(fmt.Sprintf("%s%v", OrganizationUrnPrefix, cfg.OrganizationId)),
+		organizationSocialActionEventType,
+	)
+
+	return service.urlRest(fmt.Sprintf("eventSubscriptions/%s", key))
+}
+
+// SubscribeOrganizationSocialActions registers the webhook url for social action notifications of an organization
+func (service *Service) SubscribeOrganizationSocialActions(cfg *WebhookSubscriptionConfig) *errortools.Error {
+	if service == nil {
+		return errortools.ErrorMessage("Service pointer is nil")
+	}
+	if cfg == nil {
+		return errortools.ErrorMessage("WebhookSubscriptionConfig pointer is nil")
+	}
+
+	var header = http.Header{}
+	header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+
+	requestConfig := go_http.RequestConfig{
+		Method: http.MethodPut,
+		Url:    service.webhookSubscriptionUrl(cfg),
+		BodyModel: struct {
+			Webhook string `json:"webhook"`
+		}{cfg.WebhookUrl},
+		NonDefaultHeaders: &header,
+	}
+	_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+
+	return e
+}
+
+func (service *Service) UnsubscribeOrganizationSocialActions(cfg *WebhookSubscriptionConfig) *errortools.Error {
+	if service == nil {
+		return errortools.ErrorMessage("Service pointer is nil")
+	}
+	if cfg == nil {
+		return errortools.ErrorMessage("WebhookSubscriptionConfig pointer is nil")
+	}
+
+	var header = http.Header{}
+	header.Set(restliProtocolVersionHeader, defaultRestliProtocolVersion)
+	header.Set("X-RestLi-Method", "DELETE")
+
+	requestConfig := go_http.RequestConfig{
+		Method:            http.MethodDelete,
+		Url:               service.webhookSubscriptionUrl(cfg),
+		NonDefaultHeaders: &header,
+	}
+	_, _, e := service.versionedHttpRequest(&requestConfig, nil)
+
+	return e
+}
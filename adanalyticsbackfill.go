@@ -0,0 +1,256 @@
+package linkedin
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/civil"
+	errortools "github.com/leapforce-libraries/go_errortools"
+)
+
+const (
+	defaultRestatementDays int = 7
+	defaultBackfillDays    int = 30
+)
+
+// AdAnalyticsCheckpointStore persists the last date for which analytics were fetched,
+// GetAdAnalyticsCheckpoint returns nil if no checkpoint exists for key yet
+type AdAnalyticsCheckpointStore interface {
+	GetAdAnalyticsCheckpoint(key string) (*civil.Date, *errortools.Error)
+	SaveAdAnalyticsCheckpoint(key string, date civil.Date) *errortools.Error
+}
+
+// MemoryAdAnalyticsCheckpointStore keeps checkpoints in memory
+type MemoryAdAnalyticsCheckpointStore struct {
+	mutex       sync.Mutex
+	checkpoints map[string]civil.Date
+}
+
+func NewMemoryAdAnalyticsCheckpointStore() *MemoryAdAnalyticsCheckpointStore {
+	return &MemoryAdAnalyticsCheckpointStore{
+		checkpoints: make(map[string]civil.Date),
+	}
+}
+
+func (store *MemoryAdAnalyticsCheckpointStore) GetAdAnalyticsCheckpoint(key string) (*civil.Date, *errortools.Error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	date, ok := store.checkpoints[key]
+	if !ok {
+		return nil, nil
+	}
+
+	return &date, nil
+}
+
+func (store *MemoryAdAnalyticsCheckpointStore) SaveAdAnalyticsCheckpoint(key string, date civil.Date) *errortools.Error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.checkpoints[key] = date
+
+	return nil
+}
+
+// AdAnalyticsKey uniquely identifies a daily AdAnalytics row
+type AdAnalyticsKey struct {
+	Pivot      AdAnalyticsPivot `json:"pivot"`
+	PivotValue string           `json:"pivotValue"`
+	Date       civil.Date       `json:"date"`
+}
+
+type AdAnalyticsUpsert struct {
+	Key         AdAnalyticsKey `json:"key"`
+	AdAnalytics AdAnalytics    `json:"adAnalytics"`
+}
+
+type AdAnalyticsBackfillConfig struct {
+	Service         *Service
+	CheckpointStore AdAnalyticsCheckpointStore
+	Pivot           AdAnalyticsPivot
+	AccountIds      []int64
+	StartDate       civil.Date
+	RestatementDays *int // number of trailing days that are re-fetched on each run, default 7
+	BackfillDays    *int // number of days fetched per call, default 30
+	Fields          *[]string
+	Now             func() time.Time // defaults to time.Now
+}
+
+// AdAnalyticsBackfill fetches daily analytics history once and on each later run re-fetches the trailing restatement window
+type AdAnalyticsBackfill struct {
+	service         *Service
+	checkpointStore AdAnalyticsCheckpointStore
+	pivot           AdAnalyticsPivot
+	accountIds      []int64
+	startDate       civil.Date
+	restatementDays int
+	backfillDays    int
+	fields          *[]string
+	now             func() time.Time
+}
+
+func NewAdAnalyticsBackfill(config *AdAnalyticsBackfillConfig) (*AdAnalyticsBackfill, *errortools.Error) {
+	if config == nil {
+		return nil, errortools.ErrorMessage("AdAnalyticsBackfillConfig must not be a nil pointer")
+	}
+	if config.Service == nil {
+		return nil, errortools.ErrorMessage("Service must not be nil")
+	}
+	if config.CheckpointStore == nil {
+		return nil, errortools.ErrorMessage("CheckpointStore must not be nil")
+	}
+	if !config.StartDate.IsValid() {
+		return nil, errortools.ErrorMessage("StartDate is invalid")
+	}
+
+	backfill := AdAnalyticsBackfill{
+		service:         config.Service,
+		checkpointStore: config.CheckpointStore,
+		pivot:           config.Pivot,
+		accountIds:      config.AccountIds,
+		startDate:       config.StartDate,
+		restatementDays: defaultRestatementDays,
+		backfillDays:    defaultBackfillDays,
+		now:             time.Now,
+	}
+
+	if backfill.pivot == "" {
+		backfill.pivot = AdAnalyticsPivotCreative
+	}
+	if config.RestatementDays != nil {
+		if *config.RestatementDays < 0 {
+			return nil, errortools.ErrorMessage("RestatementDays must not be negative")
+		}
+		backfill.restatementDays = *config.RestatementDays
+	}
+	if config.BackfillDays != nil {
+		if *config.BackfillDays < 1 {
+			return nil, errortools.ErrorMessage("BackfillDays must be at least 1")
+		}
+		backfill.backfillDays = *config.BackfillDays
+	}
+	if config.Fields != nil {
+		// pivotValues and dateRange are required to build the upsert key
+		fields := append([]string{}, *config.Fields...)
+		for _, required := range []string{"pivotValues", "dateRange"} {
+			found := false
+			for _, field := range fields {
+				if field == required {
+					found = true
+					break
+				}
+			}
+			if !found {
+				fields = append(fields, required)
+			}
+		}
+		backfill.fields = &fields
+	}
+	if config.Now != nil {
+		backfill.now = config.Now
+	}
+
+	return &backfill, nil
+}
+
+func (backfill *AdAnalyticsBackfill) checkpointKey(accountId int64) string {
+	return fmt.Sprintf("%s:%v", backfill.pivot, accountId)
+}
+
+// Run fetches all outstanding days for each account and passes them to upsert per chunk,
+// the checkpoint is only saved after upsert returned without error
+func (backfill *AdAnalyticsBackfill) Run(upsert func(accountId int64, upserts []AdAnalyticsUpsert) *errortools.Error) *errortools.Error {
+	if upsert == nil {
+		return errortools.ErrorMessage("upsert function must not be nil")
+	}
+
+	for _, accountId := range backfill.accountIds {
+		e := backfill.runAccount(accountId, upsert)
+		if e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
+
+func (backfill *AdAnalyticsBackfill) runAccount(accountId int64, upsert func(accountId int64, upserts []AdAnalyticsUpsert) *errortools.Error) *errortools.Error {
+	key := backfill.checkpointKey(accountId)
+
+	checkpoint, e := backfill.checkpointStore.GetAdAnalyticsCheckpoint(key)
+	if e != nil {
+		return e
+	}
+
+	today := civil.DateOf(backfill.now().UTC())
+	startDate := backfill.startDate
+
+	if checkpoint != nil {
+		// re-fetch the restatement window preceding the checkpoint
+		restatementStart := checkpoint.AddDays(1 - backfill.restatementDays)
+		if restatementStart.After(startDate) {
+			startDate = restatementStart
+		}
+	}
+
+	accounts := []string{fmt.Sprintf("%s%v", AccountUrnPrefix, accountId)}
+
+	for !startDate.After(today) {
+		endDate := startDate.AddDays(backfill.backfillDays - 1)
+		if endDate.After(today) {
+			endDate = today
+		}
+
+		adAnalytics, e := backfill.service.GetAdAnalytics(&GetAdAnalyticsConfig{
+			Pivot: backfill.pivot,
+			DateRange: AdDateRange{
+				Start: NewAdDate(&startDate),
+				End:   NewAdDate(&endDate),
+			},
+			TimeGranularity: TimeGranularityDaily,
+			Accounts:        &accounts,
+			Fields:          backfill.fields,
+		})
+		if e != nil {
+			return e
+		}
+
+		var upserts []AdAnalyticsUpsert
+		for _, row := range *adAnalytics {
+			upserts = append(upserts, AdAnalyticsUpsert{
+				Key:         backfill.key(&row),
+				AdAnalytics: row,
+			})
+		}
+
+		e = upsert(accountId, upserts)
+		if e != nil {
+			return e
+		}
+
+		e = backfill.checkpointStore.SaveAdAnalyticsCheckpoint(key, endDate)
+		if e != nil {
+			return e
+		}
+
+		startDate = endDate.AddDays(1)
+	}
+
+	return nil
+}
+
+func (backfill *AdAnalyticsBackfill) key(adAnalytics *AdAnalytics) AdAnalyticsKey {
+	var key = AdAnalyticsKey{
+		Pivot: backfill.pivot,
+	}
+	if len(adAnalytics.PivotValues) > 0 {
+		key.PivotValue = adAnalytics.PivotValues[0]
+	}
+	if date := adAnalytics.DateRange.Start.ToDate(); date != nil {
+		key.Date = *date
+	}
+
+	return key
+}
@@ -0,0 +1,269 @@
+package linkedin
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+
+	errortools "github.com/leapforce-libraries/go_errortools"
+	go_types "github.com/leapforce-libraries/go_types"
+)
+
+// AdAnalyticsRatios holds the ratio metrics derived from the additive metrics of an AdAnalytics row
+type AdAnalyticsRatios struct {
+	ClickThroughRate         float64 `json:"clickThroughRate"`
+	CostPerClick             float64 `json:"costPerClick"`
+	CostPerMille             float64 `json:"costPerMille"`
+	ConversionRate           float64 `json:"conversionRate"`
+	CostPerConversion        float64 `json:"costPerConversion"`
+	CostPerLead              float64 `json:"costPerLead"`
+	EngagementRate           float64 `json:"engagementRate"`
+	VideoCompletionRate      float64 `json:"videoCompletionRate"`
+	ReturnOnAdSpend          float64 `json:"returnOnAdSpend"`
+	LeadFormCompletionRate   float64 `json:"leadFormCompletionRate"`
+	ViralClickThroughRate    float64 `json:"viralClickThroughRate"`
+	ViralEngagementRate      float64 `json:"viralEngagementRate"`
+	ViralVideoCompletionRate float64 `json:"viralVideoCompletionRate"`
+}
+
+func ratio(numerator float64, denominator float64) float64 {
+	if denominator == 0 {
+		return 0
+	}
+
+	return numerator / denominator
+}
+
+// Ratios computes the ratio metrics from the (summed) additive metrics
+//
+// Ratios must never be summed or averaged, always recompute them from the totals.
+func (a AdAnalytics) Ratios() AdAnalyticsRatios {
+	cost := a.CostInLocalCurrency.Value()
+
+	return AdAnalyticsRatios{
+		ClickThroughRate:         ratio(float64(a.Clicks), float64(a.Impressions)),
+		CostPerClick:             ratio(cost, float64(a.Clicks)),
+		CostPerMille:             ratio(cost*1000, float64(a.Impressions)),
+		ConversionRate:           ratio(float64(a.ExternalWebsiteConversions), float64(a.Clicks)),
+		CostPerConversion:        ratio(cost, float64(a.ExternalWebsiteConversions)),
+		CostPerLead:              ratio(cost, float64(a.OneClickLeads)),
+		EngagementRate:           ratio(float64(a.TotalEngagements), float64(a.Impressions)),
+		VideoCompletionRate:      ratio(float64(a.VideoCompletions), float64(a.VideoStarts)),
+		ReturnOnAdSpend:          ratio(a.ConversionValueInLocalCurrency.Value(), cost),
+		LeadFormCompletionRate:   ratio(float64(a.OneClickLeads), float64(a.OneClickLeadFormOpens)),
+		ViralClickThroughRate:    ratio(float64(a.ViralClicks), float64(a.ViralImpressions)),
+		ViralEngagementRate:      ratio(float64(a.ViralTotalEngagements), float64(a.ViralImpressions)),
+		ViralVideoCompletionRate: ratio(float64(a.ViralVideoCompletions), float64(a.ViralVideoStarts)),
+	}
+}
+
+var float64StringType = reflect.TypeOf(go_types.Float64String{})
+
+// addAdAnalytics adds all additive metrics of source to target and widens the DateRange of target
+func addAdAnalytics(target *AdAnalytics, source *AdAnalytics) {
+	t := reflect.ValueOf(target).Elem()
+	s := reflect.ValueOf(source).Elem()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		switch {
+		case field.Kind() == reflect.Int64:
+			field.SetInt(field.Int() + s.Field(i).Int())
+		case field.Type() == float64StringType:
+			field.Set(reflect.ValueOf(addFloat64Strings(field.Interface().(go_types.Float64String), s.Field(i).Interface().(go_types.Float64String))))
+		}
+	}
+
+	if source.DateRange.Start != nil {
+		if target.DateRange.Start == nil || adDateBefore(source.DateRange.Start, target.DateRange.Start) {
+			start := *source.DateRange.Start
+			target.DateRange.Start = &start
+		}
+	}
+	if source.DateRange.End != nil {
+		if target.DateRange.End == nil || adDateBefore(target.DateRange.End, source.DateRange.End) {
+			end := *source.DateRange.End
+			target.DateRange.End = &end
+		}
+	}
+}
+
+// addFloat64Strings sums the shortest decimal representations of a and b exactly, so 0.1 + 0.2 gives 0.3,
+// the sum is rounded to a float64 again so rounding errors can still accumulate over many additions
+func addFloat64Strings(a go_types.Float64String, b go_types.Float64String) go_types.Float64String {
+	x, e := NewMoneyFromFloat64String(a, "")
+	if e != nil {
+		return go_types.NewFloat64String(a.Value() + b.Value())
+	}
+	y, e := NewMoneyFromFloat64String(b, "")
+	if e != nil {
+		return go_types.NewFloat64String(a.Value() + b.Value())
+	}
+
+	f, _ := new(big.Rat).Add(x.Rat(), y.Rat()).Float64()
+
+	return go_types.NewFloat64String(f)
+}
+
+func adDateBefore(d1 *AdDate, d2 *AdDate) bool {
+	return d1.ToDate().Before(*d2.ToDate())
+}
+
+// AdAnalyticsNode is a node in the account → campaign group → campaign → creative hierarchy
+type AdAnalyticsNode struct {
+	Level    AdAnalyticsPivot   `json:"level"`
+	Urn      string             `json:"urn"`
+	Name     string             `json:"name,omitempty"`
+	Metrics  AdAnalytics        `json:"metrics"`
+	Ratios   AdAnalyticsRatios  `json:"ratios"`
+	Children []*AdAnalyticsNode `json:"children,omitempty"`
+}
+
+type AdAnalyticsRollUp struct {
+	Accounts []*AdAnalyticsNode `json:"accounts"`
+	// Orphans are nodes below account level whose parent is unknown, e.g. the campaign of a creative
+	// when the campaign was not passed in Campaigns, their metrics are not part of any account
+	Orphans []*AdAnalyticsNode `json:"orphans,omitempty"`
+	// rows of which the creative could not be found in the passed creatives
+	Unmatched []AdAnalytics `json:"unmatched,omitempty"`
+	nodes     map[string]*AdAnalyticsNode
+}
+
+// Node returns the node with the given urn, or nil if it is not part of the roll-up
+func (rollUp *AdAnalyticsRollUp) Node(urn string) *AdAnalyticsNode {
+	if rollUp == nil {
+		return nil
+	}
+
+	return rollUp.nodes[urn]
+}
+
+type RollUpAdAnalyticsConfig struct {
+	CampaignGroups []AdCampaignGroup
+	Campaigns      []AdCampaign
+	Creatives      []AdCreative
+	// rows retrieved with AdAnalyticsPivotCreative
+	AdAnalytics []AdAnalytics
+}
+
+// RollUpAdAnalytics sums creative level analytics up to campaign, campaign group and account level
+//
+// The hierarchy is derived from the entities as returned by SearchAdCreatives, SearchAdCampaigns and SearchAdCampaignGroups,
+// so no extra API calls are needed. Nodes whose parent is not among the passed entities are returned in Orphans.
+func RollUpAdAnalytics(config *RollUpAdAnalyticsConfig) (*AdAnalyticsRollUp, *errortools.Error) {
+	if config == nil {
+		return nil, errortools.ErrorMessage("RollUpAdAnalyticsConfig must not be nil")
+	}
+
+	var rollUp = AdAnalyticsRollUp{
+		nodes: make(map[string]*AdAnalyticsNode),
+	}
+
+	var getNode = func(level AdAnalyticsPivot, urn string, name string) *AdAnalyticsNode {
+		if urn == "" {
+			return nil
+		}
+		node, ok := rollUp.nodes[urn]
+		if !ok {
+			node = &AdAnalyticsNode{
+				Level: level,
+				Urn:   urn,
+			}
+			node.Metrics.PivotValues = []string{urn}
+			rollUp.nodes[urn] = node
+		}
+		if name != "" {
+			node.Name = name
+		}
+
+		return node
+	}
+
+	var parents = make(map[string]string)
+
+	for _, campaignGroup := range config.CampaignGroups {
+		urn := fmt.Sprintf("%s%v", CampaignGroupUrnPrefix, campaignGroup.Id)
+		getNode(AdAnalyticsPivotAccount, campaignGroup.Account, "")
+		getNode(AdAnalyticsPivotCampaignGroup, urn, campaignGroup.Name)
+		parents[urn] = campaignGroup.Account
+	}
+
+	for _, campaign := range config.Campaigns {
+		urn := fmt.Sprintf("%s%v", CampaignUrnPrefix, campaign.Id)
+		getNode(AdAnalyticsPivotAccount, campaign.Account, "")
+		getNode(AdAnalyticsPivotCampaignGroup, campaign.CampaignGroup, "")
+		getNode(AdAnalyticsPivotCampaign, urn, campaign.Name)
+		parents[urn] = campaign.CampaignGroup
+		if _, ok := parents[campaign.CampaignGroup]; !ok && campaign.CampaignGroup != "" {
+			parents[campaign.CampaignGroup] = campaign.Account
+		}
+	}
+
+	for _, creative := range config.Creatives {
+		if creative.Id == nil || creative.Campaign == nil {
+			continue
+		}
+		getNode(AdAnalyticsPivotCampaign, *creative.Campaign, "")
+		getNode(AdAnalyticsPivotCreative, *creative.Id, "")
+		parents[*creative.Id] = *creative.Campaign
+	}
+
+	// link children to their parents
+	for urn, parentUrn := range parents {
+		parent, ok := rollUp.nodes[parentUrn]
+		if !ok {
+			continue
+		}
+		parent.Children = append(parent.Children, rollUp.nodes[urn])
+	}
+
+	for urn, node := range rollUp.nodes {
+		if node.Level == AdAnalyticsPivotAccount {
+			rollUp.Accounts = append(rollUp.Accounts, node)
+			continue
+		}
+		if _, ok := rollUp.nodes[parents[urn]]; !ok {
+			rollUp.Orphans = append(rollUp.Orphans, node)
+		}
+	}
+
+	for i := range config.AdAnalytics {
+		row := &config.AdAnalytics[i]
+
+		if len(row.PivotValues) == 0 {
+			rollUp.Unmatched = append(rollUp.Unmatched, *row)
+			continue
+		}
+		node, ok := rollUp.nodes[row.PivotValues[0]]
+		if !ok || node.Level != AdAnalyticsPivotCreative {
+			rollUp.Unmatched = append(rollUp.Unmatched, *row)
+			continue
+		}
+
+		// add row to creative and all of its ancestors
+		for urn := row.PivotValues[0]; urn != ""; urn = parents[urn] {
+			node, ok := rollUp.nodes[urn]
+			if !ok {
+				break
+			}
+			addAdAnalytics(&node.Metrics, row)
+		}
+	}
+
+	for _, node := range rollUp.nodes {
+		node.Ratios = node.Metrics.Ratios()
+		sortAdAnalyticsNodes(node.Children)
+	}
+	sortAdAnalyticsNodes(rollUp.Accounts)
+	sortAdAnalyticsNodes(rollUp.Orphans)
+
+	return &rollUp, nil
+}
+
+func sortAdAnalyticsNodes(nodes []*AdAnalyticsNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Urn < nodes[j].Urn
+	})
+}
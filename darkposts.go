@@ -0,0 +1,98 @@
+package linkedin
+
+import (
+	"fmt"
+
+	errortools "github.com/leapforce-libraries/go_errortools"
+)
+
+const (
+	feedDistributionNone string = "NONE"
+	intendedStatusActive string = "ACTIVE"
+)
+
+type CreateDarkPostCreativeConfig struct {
+	AccountId                int64
+	CampaignId               int64
+	Author                   string // organization urn
+	Name                     string // dscName, shown in Campaign Manager
+	AdType                   DscAdType
+	Commentary               string
+	Content                  *PostContent // e.g. a PostContentMedia referencing an uploaded image or video
+	ContentCallToActionLabel string
+	ContentLandingPage       string
+	IntendedStatus           *string // default ACTIVE
+}
+
+type DarkPostCreative struct {
+	PostUrn     string `json:"postUrn"`
+	CreativeUrn string `json:"creativeUrn"`
+}
+
+// CreateDarkPostCreative creates a direct sponsored content (dark) post and a creative referencing it in the given campaign
+//
+// If the creative cannot be created the post is deleted again.
+func (service *Service) CreateDarkPostCreative(cfg *CreateDarkPostCreativeConfig) (*DarkPostCreative, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+	if cfg == nil {
+		return nil, errortools.ErrorMessage("CreateDarkPostCreativeConfig pointer is nil")
+	}
+	if cfg.Author == "" {
+		return nil, errortools.ErrorMessage("Author is required")
+	}
+
+	adType := cfg.AdType
+	if adType == "" {
+		adType = DscAdTypeStandard
+	}
+
+	post := Post{
+		Author:                   cfg.Author,
+		Commentary:               cfg.Commentary,
+		Content:                  cfg.Content,
+		ContentCallToActionLabel: cfg.ContentCallToActionLabel,
+		ContentLandingPage:       cfg.ContentLandingPage,
+		Visibility:               string(PostVisibilityPublic),
+		LifecycleState:           string(PostLifecycleStatePublished),
+		Distribution: PostDistribution{
+			FeedDistribution: feedDistributionNone,
+		},
+		AdContext: &PostAdContext{
+			DscAdAccount: fmt.Sprintf("%s%v", AccountUrnPrefix, cfg.AccountId),
+			DscAdType:    string(adType),
+			DscName:      cfg.Name,
+		},
+	}
+
+	postUrn, e := service.CreatePost(&post)
+	if e != nil {
+		return nil, e
+	}
+
+	intendedStatus := intendedStatusActive
+	if cfg.IntendedStatus != nil {
+		intendedStatus = *cfg.IntendedStatus
+	}
+	campaign := fmt.Sprintf("%s%v", CampaignUrnPrefix, cfg.CampaignId)
+
+	creativeUrn, e := service.CreateAdCreative(cfg.AccountId, &AdCreative{
+		Campaign:       &campaign,
+		Content:        &AdCreativeContent{Reference: postUrn},
+		IntendedStatus: &intendedStatus,
+	})
+	if e != nil {
+		// roll back the post so no orphaned dark posts remain
+		e2 := service.DeletePost(postUrn)
+		if e2 != nil {
+			return nil, errortools.ErrorMessagef("%s (rollback of post %s failed: %s)", e.Message(), postUrn, e2.Message())
+		}
+		return nil, e
+	}
+
+	return &DarkPostCreative{
+		PostUrn:     postUrn,
+		CreativeUrn: creativeUrn,
+	}, nil
+}
@@ -0,0 +1,243 @@
+package linkedin
+
+import (
+	"strings"
+	"unicode"
+)
+
+// littleTextReservedCharacters must be escaped with a backslash in little text, see:
+// https://learn.microsoft.com/en-us/linkedin/marketing/community-management/shares/little-text-format
+const littleTextReservedCharacters string = `\|{}@[]()<>#*_~`
+
+// EscapeLittleText escapes all reserved characters in s so it is rendered as plain text
+func EscapeLittleText(s string) string {
+	var sb strings.Builder
+
+	for _, r := range s {
+		if strings.ContainsRune(littleTextReservedCharacters, r) {
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
+// LittleTextBuilder composes post commentary from plain text, mentions and hashtags
+type LittleTextBuilder struct {
+	sb strings.Builder
+}
+
+func NewLittleTextBuilder() *LittleTextBuilder {
+	return &LittleTextBuilder{}
+}
+
+// Text appends plain text, reserved characters are escaped
+func (builder *LittleTextBuilder) Text(text string) *LittleTextBuilder {
+	builder.sb.WriteString(EscapeLittleText(text))
+	return builder
+}
+
+// Mention appends a mention of a member (urn:li:person) or organization (urn:li:organization),
+// name must match the name of the mentioned entity for the mention to be rendered as a link
+func (builder *LittleTextBuilder) Mention(name string, urn string) *LittleTextBuilder {
+	builder.sb.WriteString("@[")
+	builder.sb.WriteString(EscapeLittleText(name))
+	builder.sb.WriteString("](")
+	builder.sb.WriteString(urn)
+	builder.sb.WriteString(")")
+	return builder
+}
+
+// Hashtag appends a hashtag, a leading # in tag is ignored
+func (builder *LittleTextBuilder) Hashtag(tag string) *LittleTextBuilder {
+	builder.sb.WriteString(`{hashtag|\#|`)
+	builder.sb.WriteString(EscapeLittleText(strings.TrimPrefix(tag, "#")))
+	builder.sb.WriteString("}")
+	return builder
+}
+
+func (builder *LittleTextBuilder) String() string {
+	return builder.sb.String()
+}
+
+// LittleTextMention is a mention found in little text, Start and Length are in runes of LittleText.Text
+type LittleTextMention struct {
+	Name   string `json:"name"`
+	Urn    string `json:"urn"`
+	Start  int    `json:"start"`
+	Length int    `json:"length"`
+}
+
+// LittleTextHashtag is a hashtag (without #) found in little text, Start and Length are in runes of LittleText.Text and include the #
+type LittleTextHashtag struct {
+	Tag    string `json:"tag"`
+	Start  int    `json:"start"`
+	Length int    `json:"length"`
+}
+
+// LittleText is the parsed form of little text
+type LittleText struct {
+	Text     string              `json:"text"` // unescaped text as rendered by LinkedIn
+	Mentions []LittleTextMention `json:"mentions"`
+	Hashtags []LittleTextHashtag `json:"hashtags"`
+}
+
+// ParseLittleText extracts mentions and hashtags from little text such as Post.Commentary
+//
+// Both the {hashtag|\#|tag} template and bare #tag hashtags are recognized.
+func ParseLittleText(s string) LittleText {
+	var parser = littleTextParser{input: []rune(s)}
+	parser.parse()
+
+	return LittleText{
+		Text:     parser.text.String(),
+		Mentions: parser.mentions,
+		Hashtags: parser.hashtags,
+	}
+}
+
+func (post *Post) ParseCommentary() LittleText {
+	return ParseLittleText(post.Commentary)
+}
+
+func (message *CommentMessage) Parse() LittleText {
+	return ParseLittleText(message.Text)
+}
+
+type littleTextParser struct {
+	input    []rune
+	pos      int
+	text     strings.Builder
+	length   int
+	mentions []LittleTextMention
+	hashtags []LittleTextHashtag
+}
+
+func (parser *littleTextParser) write(s string) {
+	parser.text.WriteString(s)
+	parser.length += len([]rune(s))
+}
+
+func (parser *littleTextParser) parse() {
+	for parser.pos < len(parser.input) {
+		r := parser.input[parser.pos]
+
+		switch r {
+		case '\\':
+			if parser.pos+1 < len(parser.input) {
+				parser.write(string(parser.input[parser.pos+1]))
+				parser.pos += 2
+				continue
+			}
+		case '@':
+			if parser.parseMention() {
+				continue
+			}
+		case '{':
+			if parser.parseHashtagTemplate() {
+				continue
+			}
+		case '#':
+			if parser.parseBareHashtag() {
+				continue
+			}
+		}
+
+		parser.write(string(r))
+		parser.pos++
+	}
+}
+
+// readUntil reads escaped text up to the unescaped delimiter, starting at from
+func (parser *littleTextParser) readUntil(from int, delimiter rune) (string, int, bool) {
+	var sb strings.Builder
+
+	for i := from; i < len(parser.input); i++ {
+		r := parser.input[i]
+		if r == '\\' && i+1 < len(parser.input) {
+			i++
+			sb.WriteRune(parser.input[i])
+			continue
+		}
+		if r == delimiter {
+			return sb.String(), i, true
+		}
+		sb.WriteRune(r)
+	}
+
+	return "", 0, false
+}
+
+// parseMention parses @[Name](urn)
+func (parser *littleTextParser) parseMention() bool {
+	if parser.pos+1 >= len(parser.input) || parser.input[parser.pos+1] != '[' {
+		return false
+	}
+	name, end, ok := parser.readUntil(parser.pos+2, ']')
+	if !ok || end+1 >= len(parser.input) || parser.input[end+1] != '(' {
+		return false
+	}
+	urn, end, ok := parser.readUntil(end+2, ')')
+	if !ok || !strings.HasPrefix(urn, "urn:") {
+		return false
+	}
+
+	parser.mentions = append(parser.mentions, LittleTextMention{
+		Name:   name,
+		Urn:    urn,
+		Start:  parser.length,
+		Length: len([]rune(name)),
+	})
+	parser.write(name)
+	parser.pos = end + 1
+
+	return true
+}
+
+// parseHashtagTemplate parses {hashtag|\#|tag}
+func (parser *littleTextParser) parseHashtagTemplate() bool {
+	const prefix = `{hashtag|\#|`
+
+	if !strings.HasPrefix(string(parser.input[parser.pos:]), prefix) {
+		return false
+	}
+	tag, end, ok := parser.readUntil(parser.pos+len([]rune(prefix)), '}')
+	if !ok || tag == "" {
+		return false
+	}
+
+	parser.addHashtag(tag)
+	parser.pos = end + 1
+
+	return true
+}
+
+// parseBareHashtag parses #tag
+func (parser *littleTextParser) parseBareHashtag() bool {
+	end := parser.pos + 1
+	for end < len(parser.input) && isHashtagRune(parser.input[end]) {
+		end++
+	}
+	if end == parser.pos+1 {
+		return false
+	}
+
+	parser.addHashtag(string(parser.input[parser.pos+1 : end]))
+	parser.pos = end
+
+	return true
+}
+
+func (parser *littleTextParser) addHashtag(tag string) {
+	parser.hashtags = append(parser.hashtags, LittleTextHashtag{
+		Tag:    tag,
+		Start:  parser.length,
+		Length: len([]rune(tag)) + 1,
+	})
+	parser.write("#" + tag)
+}
+
+func isHashtagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
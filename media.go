@@ -0,0 +1,202 @@
+package linkedin
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	errortools "github.com/leapforce-libraries/go_errortools"
+)
+
+const (
+	// mediaTransferTimeout covers uploads up to MaxAssetSize on slow connections
+	mediaTransferTimeout time.Duration = 10 * time.Minute
+	MaxImageSize         int64         = 36 << 20 // bytes
+	MaxImagePixels       int           = 36152320
+	MaxAssetSize         int64         = 200 << 20 // bytes
+)
+
+// mediaHttpClient downloads and uploads media files
+var mediaHttpClient = &http.Client{Timeout: mediaTransferTimeout}
+
+var supportedImageContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+var supportedAssetContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"video/mp4":  true,
+}
+
+// downloadMedia downloads the file at mediaUrl, failing on non-2xx responses and files larger than maxSize
+func downloadMedia(mediaUrl string, maxSize int64) ([]byte, *errortools.Error) {
+	resp, err := mediaHttpClient.Get(mediaUrl)
+	if err != nil {
+		return nil, errortools.ErrorMessage(err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, errortools.ErrorMessagef("Downloading %s returned statuscode %v", mediaUrl, resp.StatusCode)
+	}
+	if resp.ContentLength > maxSize {
+		return nil, errortools.ErrorMessagef("%s exceeds the maximum size of %v bytes", mediaUrl, maxSize)
+	}
+
+	return readMedia(resp.Body, -1, maxSize)
+}
+
+// openMediaUrl opens the file at mediaUrl for streaming, failing on non-2xx responses and files larger than maxSize,
+// size is -1 if the server did not send a Content-Length, the caller must close the body
+func openMediaUrl(mediaUrl string, maxSize int64) (io.ReadCloser, int64, *errortools.Error) {
+	resp, err := mediaHttpClient.Get(mediaUrl)
+	if err != nil {
+		return nil, 0, errortools.ErrorMessage(err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, 0, errortools.ErrorMessagef("Downloading %s returned statuscode %v", mediaUrl, resp.StatusCode)
+	}
+	if resp.ContentLength > maxSize {
+		resp.Body.Close()
+		return nil, 0, errortools.ErrorMessagef("%s exceeds the maximum size of %v bytes", mediaUrl, maxSize)
+	}
+
+	return resp.Body, resp.ContentLength, nil
+}
+
+// sniffMedia detects the content type of the media read from reader without consuming it,
+// the returned reader must be used instead of reader
+func sniffMedia(reader io.Reader, supportedContentTypes map[string]bool) (string, io.Reader, *errortools.Error) {
+	bufferedReader := bufio.NewReaderSize(reader, 512)
+
+	b, err := bufferedReader.Peek(512)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return "", nil, errortools.ErrorMessage(err)
+	}
+	if len(b) == 0 {
+		return "", nil, errortools.ErrorMessage("Media is empty")
+	}
+
+	contentType := http.DetectContentType(b)
+	if !supportedContentTypes[contentType] {
+		return "", nil, errortools.ErrorMessagef("Unsupported content type '%s'", contentType)
+	}
+
+	return contentType, bufferedReader, nil
+}
+
+// streamMedia uploads size bytes read from reader to putUrl without buffering them in memory
+func (service *Service) streamMedia(putUrl string, reader io.Reader, size int64, contentType string) (*http.Response, *errortools.Error) {
+	token, e := service.ValidateToken()
+	if e != nil {
+		return nil, e
+	}
+	if token == nil || token.AccessToken == nil {
+		return nil, errortools.ErrorMessage("No access token available")
+	}
+
+	request, err := http.NewRequest(http.MethodPut, putUrl, io.LimitReader(reader, size))
+	if err != nil {
+		return nil, errortools.ErrorMessage(err)
+	}
+	request.ContentLength = size
+	request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", *token.AccessToken))
+	request.Header.Set("Content-Type", contentType)
+	request.Header.Set(linkedInVersionHeader, service.apiVersion)
+
+	response, err := mediaHttpClient.Do(request)
+	if err != nil {
+		e = errortools.ErrorMessage(err)
+		e.SetRequest(request)
+		return nil, e
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		e = errortools.ErrorMessagef("Uploading to %s returned statuscode %v", putUrl, response.StatusCode)
+		e.SetRequest(request)
+		e.SetResponse(response)
+		return nil, e
+	}
+
+	return response, nil
+}
+
+// readMedia reads the media from reader, size being the expected number of bytes or -1 if unknown
+func readMedia(reader io.Reader, size int64, maxSize int64) ([]byte, *errortools.Error) {
+	if reader == nil {
+		return nil, errortools.ErrorMessage("Reader is nil")
+	}
+	if size > maxSize {
+		return nil, errortools.ErrorMessagef("Size of %v bytes exceeds the maximum size of %v bytes", size, maxSize)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(reader, maxSize+1))
+	if err != nil {
+		return nil, errortools.ErrorMessage(err)
+	}
+	if int64(len(b)) > maxSize {
+		return nil, errortools.ErrorMessagef("Media exceeds the maximum size of %v bytes", maxSize)
+	}
+	if size >= 0 && int64(len(b)) != size {
+		return nil, errortools.ErrorMessagef("Read %v bytes, expected %v", len(b), size)
+	}
+	if len(b) == 0 {
+		return nil, errortools.ErrorMessage("Media is empty")
+	}
+
+	return b, nil
+}
+
+// openMedia opens the file at path and returns it together with its size
+func openMedia(path string) (*os.File, int64, *errortools.Error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, errortools.ErrorMessage(err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, errortools.ErrorMessage(err)
+	}
+	if info.IsDir() {
+		file.Close()
+		return nil, 0, errortools.ErrorMessagef("%s is a directory", path)
+	}
+
+	return file, info.Size(), nil
+}
+
+// validateImage detects the content type and dimensions of an image and checks whether LinkedIn supports it
+func validateImage(b []byte) (string, *image.Config, *errortools.Error) {
+	contentType := http.DetectContentType(b)
+	if !supportedImageContentTypes[contentType] {
+		return "", nil, errortools.ErrorMessagef("Unsupported image content type '%s', supported are JPEG, PNG and GIF", contentType)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(b))
+	if err != nil {
+		return "", nil, errortools.ErrorMessagef("Invalid image: %s", err.Error())
+	}
+	if config.Width*config.Height > MaxImagePixels {
+		return "", nil, errortools.ErrorMessagef("Image of %vx%v exceeds the maximum of %v pixels", config.Width, config.Height, MaxImagePixels)
+	}
+
+	return contentType, &config, nil
+}
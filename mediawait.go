@@ -0,0 +1,87 @@
+package linkedin
+
+import (
+	"time"
+
+	errortools "github.com/leapforce-libraries/go_errortools"
+)
+
+const (
+	defaultWaitForMediaTimeout         time.Duration = 15 * time.Minute
+	defaultWaitForMediaInitialInterval time.Duration = 2 * time.Second
+	defaultWaitForMediaMaxInterval     time.Duration = 30 * time.Second
+)
+
+type MediaStatus string
+
+const (
+	MediaStatusWaitingUpload    MediaStatus = "WAITING_UPLOAD"
+	MediaStatusProcessing       MediaStatus = "PROCESSING"
+	MediaStatusAvailable        MediaStatus = "AVAILABLE"
+	MediaStatusProcessingFailed MediaStatus = "PROCESSING_FAILED"
+)
+
+type WaitForMediaConfig struct {
+	Timeout         *time.Duration  // default 15 minutes
+	InitialInterval *time.Duration  // wait time before the second status check, doubled for every next check, default 2 seconds
+	MaxInterval     *time.Duration  // default 30 seconds
+	Clock           Clock           // default real time
+	Stop            <-chan struct{} // closing it cancels the wait
+}
+
+// waitForMedia calls getStatus with exponential backoff until the media is AVAILABLE,
+// processing failed or the timeout expired
+func waitForMedia(urn string, config *WaitForMediaConfig, getStatus func() (MediaStatus, *errortools.Error)) *errortools.Error {
+	timeout := defaultWaitForMediaTimeout
+	interval := defaultWaitForMediaInitialInterval
+	maxInterval := defaultWaitForMediaMaxInterval
+	var clock Clock = realClock{}
+	var stop <-chan struct{}
+
+	if config != nil {
+		if config.Timeout != nil {
+			timeout = *config.Timeout
+		}
+		if config.InitialInterval != nil {
+			interval = *config.InitialInterval
+		}
+		if config.MaxInterval != nil {
+			maxInterval = *config.MaxInterval
+		}
+		if config.Clock != nil {
+			clock = config.Clock
+		}
+		stop = config.Stop
+	}
+
+	deadline := clock.Now().Add(timeout)
+
+	for {
+		status, e := getStatus()
+		if e != nil {
+			return e
+		}
+
+		switch status {
+		case MediaStatusAvailable:
+			return nil
+		case MediaStatusProcessingFailed:
+			return errortools.ErrorMessagef("Processing of %s failed", urn)
+		}
+
+		if clock.Now().Add(interval).After(deadline) {
+			return errortools.ErrorMessagef("%s not available after %v, status is %s", urn, timeout, status)
+		}
+
+		select {
+		case <-clock.After(interval):
+		case <-stop:
+			return errortools.ErrorMessagef("Waiting for %s was stopped, status is %s", urn, status)
+		}
+
+		interval *= 2
+		if interval > maxInterval {
+			interval = maxInterval
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package linkedin
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	errortools "github.com/leapforce-libraries/go_errortools"
+)
+
+// stalledClock never fires, so only Stop can end a wait
+type stalledClock struct{}
+
+func (stalledClock) Now() time.Time {
+	return time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func (stalledClock) After(d time.Duration) <-chan time.Time {
+	return make(chan time.Time)
+}
+
+func newTestWaitForMediaConfig(clock Clock, timeout time.Duration) *WaitForMediaConfig {
+	initialInterval := 2 * time.Second
+	maxInterval := 8 * time.Second
+
+	return &WaitForMediaConfig{
+		Timeout:         &timeout,
+		InitialInterval: &initialInterval,
+		MaxInterval:     &maxInterval,
+		Clock:           clock,
+	}
+}
+
+// statusSequence returns the statuses in order, repeating the last one, and records the time of each call
+func statusSequence(clock Clock, calls *[]time.Time, statuses ...MediaStatus) func() (MediaStatus, *errortools.Error) {
+	return func() (MediaStatus, *errortools.Error) {
+		*calls = append(*calls, clock.Now())
+		i := len(*calls) - 1
+		if i >= len(statuses) {
+			i = len(statuses) - 1
+		}
+		return statuses[i], nil
+	}
+}
+
+func TestWaitForMediaCapsBackoff(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	var calls []time.Time
+
+	e := waitForMedia("urn:li:video:1", newTestWaitForMediaConfig(clock, time.Hour), statusSequence(clock, &calls,
+		MediaStatusWaitingUpload,
+		MediaStatusProcessing,
+		MediaStatusProcessing,
+		MediaStatusProcessing,
+		MediaStatusProcessing,
+		MediaStatusAvailable,
+	))
+	if e != nil {
+		t.Fatal(e.Message())
+	}
+
+	expected := []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 8 * time.Second, 8 * time.Second}
+	if len(calls) != len(expected)+1 {
+		t.Fatalf("expected %v status checks, got %v", len(expected)+1, len(calls))
+	}
+	for i, interval := range expected {
+		if d := calls[i+1].Sub(calls[i]); d != interval {
+			t.Errorf("interval %v: expected %v, got %v", i, interval, d)
+		}
+	}
+}
+
+func TestWaitForMediaTimeout(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	start := clock.Now()
+	var calls []time.Time
+
+	e := waitForMedia("urn:li:video:1", newTestWaitForMediaConfig(clock, 30*time.Second), statusSequence(clock, &calls, MediaStatusProcessing))
+	if e == nil {
+		t.Fatal("expected a timeout error")
+	}
+	if !strings.Contains(e.Message(), "not available") {
+		t.Errorf("unexpected error: %s", e.Message())
+	}
+	if elapsed := clock.Now().Sub(start); elapsed > 30*time.Second {
+		t.Errorf("waited %v, beyond the timeout", elapsed)
+	}
+	// checks at 0, 2, 6, 14, 22 and 30 seconds, the next one at 38 would pass the deadline
+	if len(calls) != 6 {
+		t.Errorf("expected 6 status checks, got %v", len(calls))
+	}
+}
+
+func TestWaitForMediaProcessingFailed(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	var calls []time.Time
+
+	e := waitForMedia("urn:li:video:1", newTestWaitForMediaConfig(clock, time.Hour), statusSequence(clock, &calls,
+		MediaStatusProcessing,
+		MediaStatusProcessingFailed,
+	))
+	if e == nil {
+		t.Fatal("expected a processing failed error")
+	}
+	if !strings.Contains(e.Message(), "failed") {
+		t.Errorf("unexpected error: %s", e.Message())
+	}
+	if len(calls) != 2 {
+		t.Errorf("expected 2 status checks, got %v", len(calls))
+	}
+}
+
+func TestWaitForMediaStop(t *testing.T) {
+	stop := make(chan struct{})
+	close(stop)
+
+	config := newTestWaitForMediaConfig(stalledClock{}, time.Hour)
+	config.Stop = stop
+
+	var calls []time.Time
+	e := waitForMedia("urn:li:video:1", config, statusSequence(stalledClock{}, &calls, MediaStatusProcessing))
+	if e == nil {
+		t.Fatal("expected a stopped error")
+	}
+	if len(calls) != 1 {
+		t.Errorf("expected 1 status check, got %v", len(calls))
+	}
+}
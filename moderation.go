@@ -0,0 +1,542 @@
+package linkedin
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	errortools "github.com/leapforce-libraries/go_errortools"
+)
+
+const (
+	defaultModerationLookback    time.Duration = 7 * 24 * time.Hour
+	defaultRepeatedTextThreshold int           = 3
+	defaultRepeatedTextMinLength int           = 10
+	defaultRepeatedTextWindow    time.Duration = 24 * time.Hour
+	maxModerationReplyDepth      int           = 2 // LinkedIn nests replies one level deep, one more level is checked to be safe
+)
+
+// ModerationRule decides whether a comment violates a rule
+type ModerationRule interface {
+	Name() string
+	Match(comment *Comment) bool
+}
+
+func commentText(comment *Comment) string {
+	if comment == nil || comment.Message == nil {
+		return ""
+	}
+
+	return comment.Message.Text
+}
+
+// KeywordRule matches comments containing any of the keywords
+type KeywordRule struct {
+	Keywords      []string
+	CaseSensitive bool
+}
+
+func (rule *KeywordRule) Name() string {
+	return "keyword"
+}
+
+func (rule *KeywordRule) Match(comment *Comment) bool {
+	text := commentText(comment)
+	if !rule.CaseSensitive {
+		text = strings.ToLower(text)
+	}
+
+	for _, keyword := range rule.Keywords {
+		if !rule.CaseSensitive {
+			keyword = strings.ToLower(keyword)
+		}
+		if keyword != "" && strings.Contains(text, keyword) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RegexRule matches comments matching any of the regular expressions
+type RegexRule struct {
+	Regexps []*regexp.Regexp
+}
+
+func (rule *RegexRule) Name() string {
+	return "regex"
+}
+
+func (rule *RegexRule) Match(comment *Comment) bool {
+	text := commentText(comment)
+
+	for _, re := range rule.Regexps {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+
+	return false
+}
+
+var linkRegexp = regexp.MustCompile(`(?i)(https?://|www\.)\S+|\b[a-z0-9-]+\.(com|net|org|io|ly|co|info|biz|xyz|ru|cn)(/\S*)?\b`)
+
+// LinkRule matches comments containing links, links to AllowedDomains or their subdomains are ignored
+type LinkRule struct {
+	AllowedDomains []string
+}
+
+func (rule *LinkRule) Name() string {
+	return "link"
+}
+
+func (rule *LinkRule) Match(comment *Comment) bool {
+	for _, link := range linkRegexp.FindAllString(commentText(comment), -1) {
+		if !rule.allowed(link) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (rule *LinkRule) allowed(link string) bool {
+	if !strings.Contains(link, "://") {
+		link = "http://" + link
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "" {
+		return false
+	}
+
+	for _, domain := range rule.AllowedDomains {
+		domain = strings.Trim(strings.ToLower(strings.TrimSpace(domain)), ".")
+		if domain == "" {
+			continue
+		}
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RepeatedTextRule matches comments whose text has been seen at least Threshold times within Window, the first occurrences are not matched.
+// Counts are kept in memory per process and only cover the comments seen since it started.
+type RepeatedTextRule struct {
+	Threshold *int           // default 3
+	MinLength *int           // texts shorter than MinLength are ignored, default 10
+	Window    *time.Duration // occurrences older than Window are forgotten, default 24 hours
+	mutex     sync.Mutex
+	seen      map[string][]int64 // text → creation times (unix ms) within the window
+	latest    int64
+	lastSweep int64
+}
+
+func (rule *RepeatedTextRule) Name() string {
+	return "repeatedText"
+}
+
+func (rule *RepeatedTextRule) Match(comment *Comment) bool {
+	threshold := defaultRepeatedTextThreshold
+	if rule.Threshold != nil {
+		threshold = *rule.Threshold
+	}
+	minLength := defaultRepeatedTextMinLength
+	if rule.MinLength != nil {
+		minLength = *rule.MinLength
+	}
+	window := defaultRepeatedTextWindow
+	if rule.Window != nil {
+		window = *rule.Window
+	}
+
+	text := strings.Join(strings.Fields(strings.ToLower(commentText(comment))), " ")
+	if len([]rune(text)) < minLength {
+		return false
+	}
+
+	created := time.Now().UnixMilli()
+	if comment.Created != nil && comment.Created.Time > 0 {
+		created = comment.Created.Time
+	}
+
+	rule.mutex.Lock()
+	defer rule.mutex.Unlock()
+
+	if rule.seen == nil {
+		rule.seen = make(map[string][]int64)
+	}
+	if created > rule.latest {
+		rule.latest = created
+	}
+	cutoff := rule.latest - window.Milliseconds()
+
+	// drop texts not seen within the window once per window, so the map stays bounded
+	if rule.latest-rule.lastSweep > window.Milliseconds() {
+		for t, times := range rule.seen {
+			if times = withinWindow(times, cutoff); len(times) == 0 {
+				delete(rule.seen, t)
+			} else {
+				rule.seen[t] = times
+			}
+		}
+		rule.lastSweep = rule.latest
+	}
+
+	if created < cutoff {
+		return false
+	}
+
+	times := append(withinWindow(rule.seen[text], cutoff), created)
+	rule.seen[text] = times
+
+	return len(times) >= threshold
+}
+
+func withinWindow(times []int64, cutoff int64) []int64 {
+	var kept []int64
+	for _, t := range times {
+		if t >= cutoff {
+			kept = append(kept, t)
+		}
+	}
+
+	return kept
+}
+
+type ModerationAction string
+
+const (
+	ModerationActionReport ModerationAction = "REPORT"
+	ModerationActionHide   ModerationAction = "HIDE"
+	ModerationActionDelete ModerationAction = "DELETE"
+)
+
+// ModerationPolicy applies Action to comments matching Rule and optionally replies to them as the organization,
+// Reply is ignored for ModerationActionDelete
+type ModerationPolicy struct {
+	Rule   ModerationRule
+	Action ModerationAction
+	Reply  *string
+}
+
+type ModerationResult struct {
+	PostUrn    string           `json:"postUrn"`
+	CommentUrn string           `json:"commentUrn"`
+	Comment    Comment          `json:"comment"`
+	Rule       string           `json:"rule"`
+	Action     ModerationAction `json:"action"`
+	Replied    bool             `json:"replied"`
+	Time       time.Time        `json:"time"`
+}
+
+// ModerationLedger records which comments have been processed, and separately which have been replied to
+// so a reply is not posted again when the action failed
+type ModerationLedger interface {
+	IsProcessed(commentUrn string) (bool, *errortools.Error)
+	MarkProcessed(commentUrn string, result *ModerationResult) *errortools.Error
+	IsReplied(commentUrn string) (bool, *errortools.Error)
+	MarkReplied(commentUrn string) *errortools.Error
+}
+
+// MemoryModerationLedger keeps processed comments in memory
+type MemoryModerationLedger struct {
+	mutex     sync.Mutex
+	processed map[string]*ModerationResult
+	replied   map[string]bool
+}
+
+func NewMemoryModerationLedger() *MemoryModerationLedger {
+	return &MemoryModerationLedger{
+		processed: make(map[string]*ModerationResult),
+		replied:   make(map[string]bool),
+	}
+}
+
+func (ledger *MemoryModerationLedger) IsReplied(commentUrn string) (bool, *errortools.Error) {
+	ledger.mutex.Lock()
+	defer ledger.mutex.Unlock()
+
+	return ledger.replied[commentUrn], nil
+}
+
+func (ledger *MemoryModerationLedger) MarkReplied(commentUrn string) *errortools.Error {
+	ledger.mutex.Lock()
+	defer ledger.mutex.Unlock()
+
+	ledger.replied[commentUrn] = true
+
+	return nil
+}
+
+func (ledger *MemoryModerationLedger) IsProcessed(commentUrn string) (bool, *errortools.Error) {
+	ledger.mutex.Lock()
+	defer ledger.mutex.Unlock()
+
+	_, ok := ledger.processed[commentUrn]
+
+	return ok, nil
+}
+
+func (ledger *MemoryModerationLedger) MarkProcessed(commentUrn string, result *ModerationResult) *errortools.Error {
+	ledger.mutex.Lock()
+	defer ledger.mutex.Unlock()
+
+	ledger.processed[commentUrn] = result
+
+	return nil
+}
+
+type ModeratorConfig struct {
+	Service        *Service
+	OrganizationId int64
+	Policies       []ModerationPolicy // evaluated in order, the first matching policy is applied
+	Ledger         ModerationLedger
+	Lookback       *time.Duration // age of the posts that are checked, default 7 days
+	// Report is called for each matching comment, whatever the action
+	Report func(result *ModerationResult)
+	// Hide hides a comment, required when a policy uses ModerationActionHide since the API has no endpoint for it
+	Hide func(postUrn string, comment *Comment) *errortools.Error
+	Now  func() time.Time
+}
+
+// Moderator evaluates the comments on recent posts of an organization against moderation policies
+type Moderator struct {
+	service        *Service
+	organizationId int64
+	policies       []ModerationPolicy
+	ledger         ModerationLedger
+	lookback       time.Duration
+	report         func(result *ModerationResult)
+	hide           func(postUrn string, comment *Comment) *errortools.Error
+	now            func() time.Time
+}
+
+func NewModerator(config *ModeratorConfig) (*Moderator, *errortools.Error) {
+	if config == nil {
+		return nil, errortools.ErrorMessage("ModeratorConfig must not be a nil pointer")
+	}
+	if config.Service == nil {
+		return nil, errortools.ErrorMessage("Service must not be nil")
+	}
+	if config.Ledger == nil {
+		return nil, errortools.ErrorMessage("Ledger must not be nil")
+	}
+	for _, policy := range config.Policies {
+		if policy.Rule == nil {
+			return nil, errortools.ErrorMessage("Policy rule must not be nil")
+		}
+		if policy.Action == ModerationActionHide && config.Hide == nil {
+			return nil, errortools.ErrorMessage("Hide must be set when a policy uses ModerationActionHide")
+		}
+	}
+
+	moderator := Moderator{
+		service:        config.Service,
+		organizationId: config.OrganizationId,
+		policies:       config.Policies,
+		ledger:         config.Ledger,
+		lookback:       defaultModerationLookback,
+		report:         config.Report,
+		hide:           config.Hide,
+		now:            time.Now,
+	}
+	if config.Lookback != nil {
+		moderator.lookback = *config.Lookback
+	}
+	if config.Now != nil {
+		moderator.now = config.Now
+	}
+
+	return &moderator, nil
+}
+
+// Run checks all not yet processed comments on the organization's recent posts and returns the ones that matched a policy
+func (moderator *Moderator) Run() (*[]ModerationResult, *errortools.Error) {
+	createdStart := moderator.now().Add(-moderator.lookback).UnixMilli()
+	sortBy := PostsSortByCreated
+
+	posts, e := moderator.service.PostsByOwner(&PostsByOwnerConfig{
+		OrganizationId:       moderator.organizationId,
+		SortBy:               &sortBy,
+		CreatedStartDateUnix: &createdStart,
+	})
+	if e != nil {
+		return nil, e
+	}
+
+	var results []ModerationResult
+
+	for _, post := range *posts {
+		e = moderator.moderateComments(post.Id, post.Id, 0, &results)
+		if e != nil {
+			return nil, e
+		}
+	}
+
+	return &results, nil
+}
+
+// moderateComments moderates the comments on parentUrn, a post or a comment, and recursively their replies
+func (moderator *Moderator) moderateComments(postUrn string, parentUrn string, depth int, results *[]ModerationResult) *errortools.Error {
+	var comments *[]Comment
+	var e *errortools.Error
+
+	if depth == 0 {
+		comments, e = moderator.service.GetComments(parentUrn)
+	} else {
+		comments, e = moderator.service.GetCommentReplies(parentUrn)
+	}
+	if e != nil {
+		return e
+	}
+
+	for i := range *comments {
+		comment := &(*comments)[i]
+
+		result, e := moderator.moderate(postUrn, parentUrn, comment)
+		if e != nil {
+			return e
+		}
+		if result != nil {
+			*results = append(*results, *result)
+			if result.Action == ModerationActionDelete {
+				// replies are deleted along with the comment
+				continue
+			}
+		}
+
+		if comment.Urn != nil && depth < maxModerationReplyDepth {
+			e = moderator.moderateComments(postUrn, *comment.Urn, depth+1, results)
+			if e != nil {
+				return e
+			}
+		}
+	}
+
+	return nil
+}
+
+func (moderator *Moderator) organizationUrn() string {
+	return fmt.Sprintf("%s%v", OrganizationUrnPrefix, moderator.organizationId)
+}
+
+// moderate applies the first matching policy to comment, parentUrn being the post or comment it was listed on
+func (moderator *Moderator) moderate(postUrn string, parentUrn string, comment *Comment) (*ModerationResult, *errortools.Error) {
+	var commentUrn string
+	if comment.Urn != nil {
+		commentUrn = *comment.Urn
+	} else if comment.Id != nil {
+		commentUrn = *comment.Id
+	} else {
+		return nil, nil
+	}
+
+	// never moderate the organization's own comments
+	if comment.Actor != nil && *comment.Actor == moderator.organizationUrn() {
+		return nil, nil
+	}
+
+	processed, e := moderator.ledger.IsProcessed(commentUrn)
+	if e != nil {
+		return nil, e
+	}
+	if processed {
+		return nil, nil
+	}
+
+	var result *ModerationResult
+
+	for _, policy := range moderator.policies {
+		if !policy.Rule.Match(comment) {
+			continue
+		}
+
+		result = &ModerationResult{
+			PostUrn:    postUrn,
+			CommentUrn: commentUrn,
+			Comment:    *comment,
+			Rule:       policy.Rule.Name(),
+			Action:     policy.Action,
+			Time:       moderator.now(),
+		}
+
+		e = moderator.apply(&policy, postUrn, parentUrn, commentUrn, comment, result)
+		if e != nil {
+			return nil, e
+		}
+		break
+	}
+
+	if result != nil && moderator.report != nil {
+		moderator.report(result)
+	}
+
+	e = moderator.ledger.MarkProcessed(commentUrn, result)
+	if e != nil {
+		return nil, e
+	}
+
+	return result, nil
+}
+
+func (moderator *Moderator) apply(policy *ModerationPolicy, postUrn string, parentUrn string, commentUrn string, comment *Comment, result *ModerationResult) *errortools.Error {
+	// a deleted comment cannot be replied to, so Reply is not used with ModerationActionDelete
+	if policy.Reply != nil && policy.Action != ModerationActionDelete {
+		replied, e := moderator.ledger.IsReplied(commentUrn)
+		if e != nil {
+			return e
+		}
+		if !replied {
+			// replies to a reply go to the thread of its parent comment
+			threadUrn := commentUrn
+			if parentUrn != postUrn {
+				threadUrn = parentUrn
+			}
+			e = moderator.reply(postUrn, threadUrn, *policy.Reply)
+			if e != nil {
+				return e
+			}
+			// record the reply before the action, so a failing action does not cause a second reply
+			e = moderator.ledger.MarkReplied(commentUrn)
+			if e != nil {
+				return e
+			}
+		}
+		result.Replied = true
+	}
+
+	switch policy.Action {
+	case ModerationActionHide:
+		return moderator.hide(postUrn, comment)
+	case ModerationActionDelete:
+		if comment.Id == nil {
+			return errortools.ErrorMessagef("Comment %s has no id", commentUrn)
+		}
+		return moderator.service.DeleteComment(parentUrn, *comment.Id, moderator.organizationUrn())
+	}
+
+	return nil
+}
+
+func (moderator *Moderator) reply(postUrn string, threadUrn string, text string) *errortools.Error {
+	actor := moderator.organizationUrn()
+
+	_, _, e := moderator.service.CreateComment(threadUrn, &Comment{
+		Actor:         &actor,
+		Object:        &postUrn,
+		ParentComment: &threadUrn,
+		Message:       &CommentMessage{Text: text},
+	})
+
+	return e
+}
@@ -0,0 +1,342 @@
+package linkedin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"cloud.google.com/go/civil"
+	errortools "github.com/leapforce-libraries/go_errortools"
+	go_types "github.com/leapforce-libraries/go_types"
+)
+
+var moneyAmountRegexp = regexp.MustCompile(`^[-+]?\d+(\.\d+)?$`)
+
+// currencyDecimals holds the number of minor units for currencies that deviate from the default of 2
+var currencyDecimals = map[string]int{
+	"BHD": 3,
+	"CLP": 0,
+	"IQD": 3,
+	"ISK": 0,
+	"JOD": 3,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"LYD": 3,
+	"OMR": 3,
+	"PYG": 0,
+	"TND": 3,
+	"UGX": 0,
+	"VND": 0,
+	"XAF": 0,
+	"XOF": 0,
+}
+
+// Money is an exact decimal amount in a given currency
+type Money struct {
+	amount       big.Rat
+	CurrencyCode string
+}
+
+// ParseMoney parses a LinkedIn amount string (e.g. "12.34") without loss of precision
+func ParseMoney(amount string, currencyCode string) (*Money, *errortools.Error) {
+	amount = strings.TrimSpace(amount)
+	if !moneyAmountRegexp.MatchString(amount) {
+		return nil, errortools.ErrorMessagef("Invalid amount '%s'", amount)
+	}
+
+	var money = Money{CurrencyCode: strings.ToUpper(currencyCode)}
+	if _, ok := money.amount.SetString(amount); !ok {
+		return nil, errortools.ErrorMessagef("Invalid amount '%s'", amount)
+	}
+
+	return &money, nil
+}
+
+// NewMoneyFromFloat64String converts a Float64String as returned by the API into Money, NaN and infinite amounts return an error
+func NewMoneyFromFloat64String(amount go_types.Float64String, currencyCode string) (*Money, *errortools.Error) {
+	if math.IsNaN(amount.Value()) || math.IsInf(amount.Value(), 0) {
+		return nil, errortools.ErrorMessagef("Invalid amount %v", amount.Value())
+	}
+
+	return ParseMoney(strconv.FormatFloat(amount.Value(), 'f', -1, 64), currencyCode)
+}
+
+// Rat returns a copy of the exact amount
+func (m Money) Rat() *big.Rat {
+	return new(big.Rat).Set(&m.amount)
+}
+
+func (m Money) Float64() float64 {
+	f, _ := m.amount.Float64()
+	return f
+}
+
+func (m Money) IsZero() bool {
+	return m.amount.Sign() == 0
+}
+
+// Decimals returns the number of minor units of the currency
+func (m Money) Decimals() int {
+	decimals, ok := currencyDecimals[m.CurrencyCode]
+	if !ok {
+		return 2
+	}
+
+	return decimals
+}
+
+// Amount returns the amount rounded to the minor units of the currency
+func (m Money) Amount() string {
+	return m.amount.FloatString(m.Decimals())
+}
+
+func (m Money) String() string {
+	return fmt.Sprintf("%s %s", m.Amount(), m.CurrencyCode)
+}
+
+func (m Money) Add(other Money) (*Money, *errortools.Error) {
+	if m.CurrencyCode != other.CurrencyCode {
+		return nil, errortools.ErrorMessagef("Cannot add %s to %s", other.CurrencyCode, m.CurrencyCode)
+	}
+
+	var sum = Money{CurrencyCode: m.CurrencyCode}
+	sum.amount.Add(&m.amount, &other.amount)
+
+	return &sum, nil
+}
+
+// Mul multiplies the amount by rate and assigns the result the given currency
+func (m Money) Mul(rate *big.Rat, currencyCode string) *Money {
+	var product = Money{CurrencyCode: strings.ToUpper(currencyCode)}
+	product.amount.Mul(&m.amount, rate)
+
+	return &product
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount       string `json:"amount"`
+		CurrencyCode string `json:"currencyCode"`
+	}{m.Amount(), m.CurrencyCode})
+}
+
+func (m *Money) UnmarshalJSON(b []byte) error {
+	var money struct {
+		Amount       string `json:"amount"`
+		CurrencyCode string `json:"currencyCode"`
+	}
+	err := json.Unmarshal(b, &money)
+	if err != nil {
+		return err
+	}
+
+	if money.Amount == "" {
+		*m = Money{CurrencyCode: money.CurrencyCode}
+		return nil
+	}
+
+	m_, e := ParseMoney(money.Amount, money.CurrencyCode)
+	if e != nil {
+		return errors.New(e.Message())
+	}
+	*m = *m_
+
+	return nil
+}
+
+func (b AdBudget) Money() (*Money, *errortools.Error) {
+	return ParseMoney(b.Amount, b.CurrencyCode)
+}
+
+func (v ConversionValue) Money() (*Money, *errortools.Error) {
+	return ParseMoney(v.Amount, v.CurrencyCode)
+}
+
+// FxRateProvider returns the number of units of quoteCurrency that one unit of baseCurrency buys on the given date
+type FxRateProvider interface {
+	FxRate(baseCurrency string, quoteCurrency string, date civil.Date) (*big.Rat, *errortools.Error)
+}
+
+// StaticFxRateProvider serves fixed, date independent rates relative to a single base currency
+type StaticFxRateProvider struct {
+	baseCurrency string
+	rates        map[string]*big.Rat
+}
+
+// NewStaticFxRateProvider creates a StaticFxRateProvider, rates holds the amount of each currency one unit of baseCurrency buys (e.g. "EUR": "0.92" for base "USD")
+func NewStaticFxRateProvider(baseCurrency string, rates map[string]string) (*StaticFxRateProvider, *errortools.Error) {
+	baseCurrency = strings.ToUpper(baseCurrency)
+
+	var provider = StaticFxRateProvider{
+		baseCurrency: baseCurrency,
+		rates:        map[string]*big.Rat{baseCurrency: big.NewRat(1, 1)},
+	}
+
+	for currency, rate := range rates {
+		money, e := ParseMoney(rate, currency)
+		if e != nil {
+			return nil, e
+		}
+		if money.amount.Sign() <= 0 {
+			return nil, errortools.ErrorMessagef("Rate for %s must be positive", currency)
+		}
+		provider.rates[money.CurrencyCode] = money.Rat()
+	}
+
+	return &provider, nil
+}
+
+func (provider *StaticFxRateProvider) FxRate(baseCurrency string, quoteCurrency string, date civil.Date) (*big.Rat, *errortools.Error) {
+	baseRate, ok := provider.rates[strings.ToUpper(baseCurrency)]
+	if !ok {
+		return nil, errortools.ErrorMessagef("No rate available for %s", baseCurrency)
+	}
+	quoteRate, ok := provider.rates[strings.ToUpper(quoteCurrency)]
+	if !ok {
+		return nil, errortools.ErrorMessagef("No rate available for %s", quoteCurrency)
+	}
+
+	return new(big.Rat).Quo(quoteRate, baseRate), nil
+}
+
+type CurrencyConverterConfig struct {
+	ReportingCurrency string
+	FxRateProvider    FxRateProvider
+}
+
+// CurrencyConverter converts amounts into a single reporting currency
+type CurrencyConverter struct {
+	reportingCurrency string
+	fxRateProvider    FxRateProvider
+}
+
+func NewCurrencyConverter(config *CurrencyConverterConfig) (*CurrencyConverter, *errortools.Error) {
+	if config == nil {
+		return nil, errortools.ErrorMessage("CurrencyConverterConfig must not be a nil pointer")
+	}
+	if config.ReportingCurrency == "" {
+		return nil, errortools.ErrorMessage("ReportingCurrency must not be empty")
+	}
+	if config.FxRateProvider == nil {
+		return nil, errortools.ErrorMessage("FxRateProvider must not be nil")
+	}
+
+	return &CurrencyConverter{
+		reportingCurrency: strings.ToUpper(config.ReportingCurrency),
+		fxRateProvider:    config.FxRateProvider,
+	}, nil
+}
+
+func (converter *CurrencyConverter) ReportingCurrency() string {
+	return converter.reportingCurrency
+}
+
+func (converter *CurrencyConverter) Convert(money *Money, date civil.Date) (*Money, *errortools.Error) {
+	if money == nil {
+		return nil, errortools.ErrorMessage("Money pointer is nil")
+	}
+	if money.CurrencyCode == converter.reportingCurrency {
+		var m = *money
+		return &m, nil
+	}
+	if money.CurrencyCode == "" {
+		return nil, errortools.ErrorMessage("Money has no currency code")
+	}
+
+	rate, e := converter.fxRateProvider.FxRate(money.CurrencyCode, converter.reportingCurrency, date)
+	if e != nil {
+		return nil, e
+	}
+
+	return money.Mul(rate, converter.reportingCurrency), nil
+}
+
+func (converter *CurrencyConverter) ConvertAdBudget(budget *AdBudget, date civil.Date) (*Money, *errortools.Error) {
+	if budget == nil {
+		return nil, errortools.ErrorMessage("AdBudget pointer is nil")
+	}
+
+	money, e := budget.Money()
+	if e != nil {
+		return nil, e
+	}
+
+	return converter.Convert(money, date)
+}
+
+func (converter *CurrencyConverter) ConvertConversionValue(value *ConversionValue, date civil.Date) (*Money, *errortools.Error) {
+	if value == nil {
+		return nil, errortools.ErrorMessage("ConversionValue pointer is nil")
+	}
+
+	money, e := value.Money()
+	if e != nil {
+		return nil, e
+	}
+
+	return converter.Convert(money, date)
+}
+
+// AdAnalyticsMoney holds the monetary metrics of an AdAnalytics row in the reporting currency
+type AdAnalyticsMoney struct {
+	PivotValues     []string   `json:"pivotValues"`
+	Date            civil.Date `json:"date"`
+	Cost            Money      `json:"cost"`
+	ConversionValue Money      `json:"conversionValue"`
+}
+
+// ConvertAdAnalytics converts the cost and conversion value of an AdAnalytics row, accountCurrency being the AdAccount.Currency the row was reported in
+//
+// The rate of the first day of the row's DateRange is used, rows without a DateRange return an error.
+// If the reporting currency is USD, CostInUsd as reported by LinkedIn is used for the cost.
+func (converter *CurrencyConverter) ConvertAdAnalytics(adAnalytics *AdAnalytics, accountCurrency string) (*AdAnalyticsMoney, *errortools.Error) {
+	if adAnalytics == nil {
+		return nil, errortools.ErrorMessage("AdAnalytics pointer is nil")
+	}
+
+	if adAnalytics.DateRange.Start == nil {
+		return nil, errortools.ErrorMessage("AdAnalytics has no DateRange to determine the exchange rate date, include dateRange in the fields")
+	}
+	var date = *adAnalytics.DateRange.Start.ToDate()
+
+	var cost *Money
+
+	if converter.reportingCurrency == "USD" {
+		var e *errortools.Error
+		cost, e = NewMoneyFromFloat64String(adAnalytics.CostInUsd, "USD")
+		if e != nil {
+			return nil, e
+		}
+	} else {
+		localCost, e := NewMoneyFromFloat64String(adAnalytics.CostInLocalCurrency, accountCurrency)
+		if e != nil {
+			return nil, e
+		}
+		cost, e = converter.Convert(localCost, date)
+		if e != nil {
+			return nil, e
+		}
+	}
+
+	localConversionValue, e := NewMoneyFromFloat64String(adAnalytics.ConversionValueInLocalCurrency, accountCurrency)
+	if e != nil {
+		return nil, e
+	}
+	conversionValue, e := converter.Convert(localConversionValue, date)
+	if e != nil {
+		return nil, e
+	}
+
+	return &AdAnalyticsMoney{
+		PivotValues:     adAnalytics.PivotValues,
+		Date:            date,
+		Cost:            *cost,
+		ConversionValue: *conversionValue,
+	}, nil
+}
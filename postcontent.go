@@ -0,0 +1,276 @@
+package linkedin
+
+import (
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	errortools "github.com/leapforce-libraries/go_errortools"
+)
+
+const (
+	maxPollQuestionLength    int = 140
+	maxPollOptionLength      int = 30
+	minPollOptions           int = 2
+	maxPollOptions           int = 4
+	minMultiImageImages      int = 2
+	maxMultiImageImages      int = 20
+	minCarouselCards         int = 2
+	maxCarouselCards         int = 10
+	maxArticleTitleLength    int = 400
+	maxDocumentTitleLength   int = 400
+	maxImageAltTextLength    int = 4086
+	maxCarouselCardUrlLength int = 2000
+)
+
+// PostContent holds the content of a post, exactly one of its fields must be set
+type PostContent struct {
+	Media       *PostContentMedia       `json:"media,omitempty"`
+	MultiImage  *PostContentMultiImage  `json:"multiImage,omitempty"`
+	Article     *PostContentArticle     `json:"article,omitempty"`
+	Poll        *PostContentPoll        `json:"poll,omitempty"`
+	Carousel    *PostContentCarousel    `json:"carousel,omitempty"`
+	Celebration *PostContentCelebration `json:"celebration,omitempty"`
+}
+
+// PostContentMedia holds a single image, video or document, for documents Title is required
+type PostContentMedia struct {
+	Title   string `json:"title"`
+	Id      string `json:"id"`
+	AltText string `json:"altText,omitempty"`
+}
+
+type PostContentMultiImage struct {
+	Images []PostContentMultiImageImage `json:"images"`
+}
+
+type PostContentMultiImageImage struct {
+	Id      string `json:"id"`
+	AltText string `json:"altText"`
+}
+
+type PostContentArticle struct {
+	Source           string `json:"source"`
+	Title            string `json:"title"`
+	Description      string `json:"description,omitempty"`
+	Thumbnail        string `json:"thumbnail,omitempty"`
+	ThumbnailAltText string `json:"thumbnailAltText,omitempty"`
+}
+
+type PollDuration string
+
+const (
+	PollDurationOneDay       PollDuration = "ONE_DAY"
+	PollDurationThreeDays    PollDuration = "THREE_DAYS"
+	PollDurationSevenDays    PollDuration = "SEVEN_DAYS"
+	PollDurationFourteenDays PollDuration = "FOURTEEN_DAYS"
+)
+
+type PostContentPoll struct {
+	Question          string                  `json:"question"`
+	Options           []PostContentPollOption `json:"options"`
+	Settings          PostContentPollSettings `json:"settings"`
+	UniqueVotersCount *int64                  `json:"uniqueVotersCount,omitempty"`
+}
+
+type PostContentPollOption struct {
+	Text            string `json:"text"`
+	VoteCount       *int64 `json:"voteCount,omitempty"`
+	IsVotedByViewer *bool  `json:"isVotedByViewer,omitempty"`
+}
+
+type PostContentPollSettings struct {
+	Duration               PollDuration `json:"duration"`
+	VoteSelectionType      string       `json:"voteSelectionType,omitempty"`
+	IsVoterVisibleToAuthor *bool        `json:"isVoterVisibleToAuthor,omitempty"`
+}
+
+// PostContentCarousel can only be used in sponsored (dark) posts
+type PostContentCarousel struct {
+	Cards []PostContentCarouselCard `json:"cards"`
+}
+
+type PostContentCarouselCard struct {
+	Media       PostContentMedia `json:"media"`
+	LandingPage string           `json:"landingPage"`
+}
+
+type CelebrationType string
+
+const (
+	CelebrationTypeWelcome          CelebrationType = "WELCOME"
+	CelebrationTypeWorkAnniversary  CelebrationType = "WORK_ANNIVERSARY"
+	CelebrationTypeNewPosition      CelebrationType = "NEW_POSITION"
+	CelebrationTypeCertification    CelebrationType = "CERTIFICATION"
+	CelebrationTypeEducation        CelebrationType = "EDUCATION"
+	CelebrationTypeAward            CelebrationType = "AWARD"
+	CelebrationTypeEvent            CelebrationType = "EVENT"
+	CelebrationTypeProjectLaunch    CelebrationType = "PROJECT_LAUNCH"
+	CelebrationTypeCompanyMilestone CelebrationType = "COMPANY_MILESTONE"
+)
+
+type PostContentCelebration struct {
+	Type      CelebrationType `json:"type"`
+	Recipient []string        `json:"recipient,omitempty"`
+	Image     string          `json:"image,omitempty"`
+	AltText   string          `json:"altText,omitempty"`
+}
+
+// Validate checks the content against the rules LinkedIn applies when creating a post
+func (content *PostContent) Validate() *errortools.Error {
+	if content == nil {
+		return nil
+	}
+
+	var count = 0
+	var e *errortools.Error
+
+	if content.Media != nil {
+		count++
+		e = content.Media.Validate()
+	}
+	if content.MultiImage != nil {
+		count++
+		e = content.MultiImage.Validate()
+	}
+	if content.Article != nil {
+		count++
+		e = content.Article.Validate()
+	}
+	if content.Poll != nil {
+		count++
+		e = content.Poll.Validate()
+	}
+	if content.Carousel != nil {
+		count++
+		e = content.Carousel.Validate()
+	}
+	if content.Celebration != nil {
+		count++
+		e = content.Celebration.Validate()
+	}
+
+	if count > 1 {
+		return errortools.ErrorMessage("PostContent must contain exactly one content type")
+	}
+
+	return e
+}
+
+func (media *PostContentMedia) Validate() *errortools.Error {
+	if media.Id == "" {
+		return errortools.ErrorMessage("Media id is required")
+	}
+	if utf8.RuneCountInString(media.AltText) > maxImageAltTextLength {
+		return errortools.ErrorMessagef("Media altText must not exceed %v characters", maxImageAltTextLength)
+	}
+	if strings.HasPrefix(media.Id, DocumentUrnPrefix) {
+		if media.Title == "" {
+			return errortools.ErrorMessage("Document title is required")
+		}
+		if utf8.RuneCountInString(media.Title) > maxDocumentTitleLength {
+			return errortools.ErrorMessagef("Document title must not exceed %v characters", maxDocumentTitleLength)
+		}
+	}
+
+	return nil
+}
+
+func (multiImage *PostContentMultiImage) Validate() *errortools.Error {
+	if len(multiImage.Images) < minMultiImageImages || len(multiImage.Images) > maxMultiImageImages {
+		return errortools.ErrorMessagef("MultiImage must contain %v to %v images", minMultiImageImages, maxMultiImageImages)
+	}
+	for _, image := range multiImage.Images {
+		if !strings.HasPrefix(image.Id, ImageUrnPrefix) {
+			return errortools.ErrorMessagef("MultiImage image '%s' is not an image urn", image.Id)
+		}
+		if utf8.RuneCountInString(image.AltText) > maxImageAltTextLength {
+			return errortools.ErrorMessagef("MultiImage altText must not exceed %v characters", maxImageAltTextLength)
+		}
+	}
+
+	return nil
+}
+
+func (article *PostContentArticle) Validate() *errortools.Error {
+	if !isAbsoluteUrl(article.Source) {
+		return errortools.ErrorMessage("Article source must be an absolute url")
+	}
+	if article.Title == "" {
+		return errortools.ErrorMessage("Article title is required")
+	}
+	if utf8.RuneCountInString(article.Title) > maxArticleTitleLength {
+		return errortools.ErrorMessagef("Article title must not exceed %v characters", maxArticleTitleLength)
+	}
+	if article.Thumbnail != "" && !strings.HasPrefix(article.Thumbnail, ImageUrnPrefix) {
+		return errortools.ErrorMessage("Article thumbnail must be an image urn")
+	}
+
+	return nil
+}
+
+func (poll *PostContentPoll) Validate() *errortools.Error {
+	if poll.Question == "" {
+		return errortools.ErrorMessage("Poll question is required")
+	}
+	if utf8.RuneCountInString(poll.Question) > maxPollQuestionLength {
+		return errortools.ErrorMessagef("Poll question must not exceed %v characters", maxPollQuestionLength)
+	}
+	if len(poll.Options) < minPollOptions || len(poll.Options) > maxPollOptions {
+		return errortools.ErrorMessagef("Poll must contain %v to %v options", minPollOptions, maxPollOptions)
+	}
+	for _, option := range poll.Options {
+		if option.Text == "" {
+			return errortools.ErrorMessage("Poll option text is required")
+		}
+		if utf8.RuneCountInString(option.Text) > maxPollOptionLength {
+			return errortools.ErrorMessagef("Poll option must not exceed %v characters", maxPollOptionLength)
+		}
+	}
+	switch poll.Settings.Duration {
+	case PollDurationOneDay, PollDurationThreeDays, PollDurationSevenDays, PollDurationFourteenDays:
+	default:
+		return errortools.ErrorMessagef("Invalid poll duration '%s'", poll.Settings.Duration)
+	}
+
+	return nil
+}
+
+func (carousel *PostContentCarousel) Validate() *errortools.Error {
+	if len(carousel.Cards) < minCarouselCards || len(carousel.Cards) > maxCarouselCards {
+		return errortools.ErrorMessagef("Carousel must contain %v to %v cards", minCarouselCards, maxCarouselCards)
+	}
+	for _, card := range carousel.Cards {
+		if !strings.HasPrefix(card.Media.Id, ImageUrnPrefix) {
+			return errortools.ErrorMessagef("Carousel card media '%s' is not an image urn", card.Media.Id)
+		}
+		if !isAbsoluteUrl(card.LandingPage) {
+			return errortools.ErrorMessage("Carousel card landingPage must be an absolute url")
+		}
+		if len(card.LandingPage) > maxCarouselCardUrlLength {
+			return errortools.ErrorMessagef("Carousel card landingPage must not exceed %v characters", maxCarouselCardUrlLength)
+		}
+	}
+
+	return nil
+}
+
+func (celebration *PostContentCelebration) Validate() *errortools.Error {
+	if celebration.Type == "" {
+		return errortools.ErrorMessage("Celebration type is required")
+	}
+	if celebration.Image != "" && !strings.HasPrefix(celebration.Image, ImageUrnPrefix) {
+		return errortools.ErrorMessage("Celebration image must be an image urn")
+	}
+
+	return nil
+}
+
+func isAbsoluteUrl(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
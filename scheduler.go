@@ -0,0 +1,415 @@
+package linkedin
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	errortools "github.com/leapforce-libraries/go_errortools"
+)
+
+const (
+	defaultSchedulerPollInterval   time.Duration = time.Minute
+	defaultSchedulerPublishSpacing time.Duration = 5 * time.Second
+	defaultSchedulerRetryBackoff   time.Duration = time.Minute
+	defaultSchedulerMaxAttempts    int           = 5
+)
+
+// Clock abstracts time so the Scheduler and media waits can be driven by a fake clock
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+type ScheduledPostStatus string
+
+const (
+	ScheduledPostStatusPending    ScheduledPostStatus = "PENDING"
+	ScheduledPostStatusPublishing ScheduledPostStatus = "PUBLISHING" // CreatePost was called, its outcome was not recorded yet
+	ScheduledPostStatusPublished  ScheduledPostStatus = "PUBLISHED"
+	ScheduledPostStatusFailed     ScheduledPostStatus = "FAILED"
+	ScheduledPostStatusCanceled   ScheduledPostStatus = "CANCELED"
+	// ScheduledPostStatusNeedsReview is set when CreatePost returned no response, the post might have been published,
+	// check the organization's posts and either Reschedule or Cancel it
+	ScheduledPostStatusNeedsReview ScheduledPostStatus = "NEEDS_REVIEW"
+)
+
+type ScheduledPost struct {
+	Id            string              `json:"id"`
+	Post          Post                `json:"post"`
+	PublishAt     time.Time           `json:"publishAt"`
+	Status        ScheduledPostStatus `json:"status"`
+	PostUrn       string              `json:"postUrn,omitempty"`
+	Attempts      int                 `json:"attempts"`
+	NextAttemptAt time.Time           `json:"nextAttemptAt"`
+	LastError     string              `json:"lastError,omitempty"`
+	PublishedAt   *time.Time          `json:"publishedAt,omitempty"`
+}
+
+// ScheduledPostStore persists scheduled posts, GetScheduledPost returns nil if id does not exist
+type ScheduledPostStore interface {
+	SaveScheduledPost(scheduledPost *ScheduledPost) *errortools.Error
+	GetScheduledPost(id string) (*ScheduledPost, *errortools.Error)
+	ListScheduledPosts() (*[]ScheduledPost, *errortools.Error)
+}
+
+// MemoryScheduledPostStore keeps scheduled posts in memory
+type MemoryScheduledPostStore struct {
+	mutex          sync.Mutex
+	scheduledPosts map[string]ScheduledPost
+}
+
+func NewMemoryScheduledPostStore() *MemoryScheduledPostStore {
+	return &MemoryScheduledPostStore{
+		scheduledPosts: make(map[string]ScheduledPost),
+	}
+}
+
+func (store *MemoryScheduledPostStore) SaveScheduledPost(scheduledPost *ScheduledPost) *errortools.Error {
+	if scheduledPost == nil {
+		return errortools.ErrorMessage("ScheduledPost pointer is nil")
+	}
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.scheduledPosts[scheduledPost.Id] = *scheduledPost
+
+	return nil
+}
+
+func (store *MemoryScheduledPostStore) GetScheduledPost(id string) (*ScheduledPost, *errortools.Error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	scheduledPost, ok := store.scheduledPosts[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return &scheduledPost, nil
+}
+
+func (store *MemoryScheduledPostStore) ListScheduledPosts() (*[]ScheduledPost, *errortools.Error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	var scheduledPosts []ScheduledPost
+	for _, scheduledPost := range store.scheduledPosts {
+		scheduledPosts = append(scheduledPosts, scheduledPost)
+	}
+
+	return &scheduledPosts, nil
+}
+
+// PostPublisher publishes a post and returns its urn, implemented by *Service
+type PostPublisher interface {
+	CreatePost(post *Post) (string, *errortools.Error)
+}
+
+type SchedulerConfig struct {
+	Publisher      PostPublisher
+	Store          ScheduledPostStore
+	Clock          Clock          // default real time
+	PollInterval   *time.Duration // interval at which the store is checked for due posts, default 1 minute
+	PublishSpacing *time.Duration // minimum time between two publish calls, default 5 seconds
+	RetryBackoff   *time.Duration // wait time after the first failed attempt, doubled for every next attempt, default 1 minute
+	MaxAttempts    *int           // default 5
+}
+
+// Scheduler publishes scheduled posts once their publish time has passed
+type Scheduler struct {
+	publisher      PostPublisher
+	store          ScheduledPostStore
+	clock          Clock
+	pollInterval   time.Duration
+	publishSpacing time.Duration
+	retryBackoff   time.Duration
+	maxAttempts    int
+	mutex          sync.Mutex
+	lastPublish    time.Time
+}
+
+func NewScheduler(config *SchedulerConfig) (*Scheduler, *errortools.Error) {
+	if config == nil {
+		return nil, errortools.ErrorMessage("SchedulerConfig must not be a nil pointer")
+	}
+	if config.Publisher == nil {
+		return nil, errortools.ErrorMessage("Publisher must not be nil")
+	}
+	if config.Store == nil {
+		return nil, errortools.ErrorMessage("Store must not be nil")
+	}
+
+	scheduler := Scheduler{
+		publisher:      config.Publisher,
+		store:          config.Store,
+		clock:          realClock{},
+		pollInterval:   defaultSchedulerPollInterval,
+		publishSpacing: defaultSchedulerPublishSpacing,
+		retryBackoff:   defaultSchedulerRetryBackoff,
+		maxAttempts:    defaultSchedulerMaxAttempts,
+	}
+
+	if config.Clock != nil {
+		scheduler.clock = config.Clock
+	}
+	if config.PollInterval != nil {
+		scheduler.pollInterval = *config.PollInterval
+	}
+	if config.PublishSpacing != nil {
+		scheduler.publishSpacing = *config.PublishSpacing
+	}
+	if config.RetryBackoff != nil {
+		scheduler.retryBackoff = *config.RetryBackoff
+	}
+	if config.MaxAttempts != nil {
+		if *config.MaxAttempts < 1 {
+			return nil, errortools.ErrorMessage("MaxAttempts must be at least 1")
+		}
+		scheduler.maxAttempts = *config.MaxAttempts
+	}
+
+	return &scheduler, nil
+}
+
+func newScheduledPostId() (string, *errortools.Error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", errortools.ErrorMessage(err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// Schedule stores post for publication at publishAt
+func (scheduler *Scheduler) Schedule(post *Post, publishAt time.Time) (*ScheduledPost, *errortools.Error) {
+	if post == nil {
+		return nil, errortools.ErrorMessage("Post pointer is nil")
+	}
+	if post.Content != nil {
+		e := post.Content.Validate()
+		if e != nil {
+			return nil, e
+		}
+	}
+
+	id, e := newScheduledPostId()
+	if e != nil {
+		return nil, e
+	}
+
+	scheduledPost := ScheduledPost{
+		Id:            id,
+		Post:          *post,
+		PublishAt:     publishAt,
+		Status:        ScheduledPostStatusPending,
+		NextAttemptAt: publishAt,
+	}
+
+	e = scheduler.store.SaveScheduledPost(&scheduledPost)
+	if e != nil {
+		return nil, e
+	}
+
+	return &scheduledPost, nil
+}
+
+// List returns all scheduled posts ordered by publish time
+func (scheduler *Scheduler) List() (*[]ScheduledPost, *errortools.Error) {
+	scheduledPosts, e := scheduler.store.ListScheduledPosts()
+	if e != nil {
+		return nil, e
+	}
+
+	sort.Slice(*scheduledPosts, func(i, j int) bool {
+		return (*scheduledPosts)[i].PublishAt.Before((*scheduledPosts)[j].PublishAt)
+	})
+
+	return scheduledPosts, nil
+}
+
+// getPending returns the scheduled post if it has not been published yet, posts that need review count as pending
+func (scheduler *Scheduler) getPending(id string) (*ScheduledPost, *errortools.Error) {
+	scheduledPost, e := scheduler.store.GetScheduledPost(id)
+	if e != nil {
+		return nil, e
+	}
+	if scheduledPost == nil {
+		return nil, errortools.ErrorMessagef("Scheduled post %s not found", id)
+	}
+	if scheduledPost.Status != ScheduledPostStatusPending && scheduledPost.Status != ScheduledPostStatusNeedsReview {
+		return nil, errortools.ErrorMessagef("Scheduled post %s is %s", id, scheduledPost.Status)
+	}
+
+	return scheduledPost, nil
+}
+
+// Cancel cancels a pending scheduled post or one that needs review
+func (scheduler *Scheduler) Cancel(id string) *errortools.Error {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	scheduledPost, e := scheduler.getPending(id)
+	if e != nil {
+		return e
+	}
+
+	scheduledPost.Status = ScheduledPostStatusCanceled
+
+	return scheduler.store.SaveScheduledPost(scheduledPost)
+}
+
+// Reschedule changes the publish time of a pending scheduled post or one that needs review and resets its attempts
+func (scheduler *Scheduler) Reschedule(id string, publishAt time.Time) *errortools.Error {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	scheduledPost, e := scheduler.getPending(id)
+	if e != nil {
+		return e
+	}
+
+	scheduledPost.Status = ScheduledPostStatusPending
+	scheduledPost.PublishAt = publishAt
+	scheduledPost.NextAttemptAt = publishAt
+	scheduledPost.Attempts = 0
+	scheduledPost.LastError = ""
+
+	return scheduler.store.SaveScheduledPost(scheduledPost)
+}
+
+// Run publishes due posts until stop is closed
+func (scheduler *Scheduler) Run(stop <-chan struct{}) *errortools.Error {
+	for {
+		e := scheduler.PublishDue(stop)
+		if e != nil {
+			return e
+		}
+
+		select {
+		case <-stop:
+			return nil
+		case <-scheduler.clock.After(scheduler.pollInterval):
+		}
+	}
+}
+
+// PublishDue publishes the posts that were due when it was called, respecting the publish spacing,
+// posts that become due while it runs are published by the next call
+func (scheduler *Scheduler) PublishDue(stop <-chan struct{}) *errortools.Error {
+	scheduledPosts, e := scheduler.List()
+	if e != nil {
+		return e
+	}
+
+	for _, scheduledPost := range *scheduledPosts {
+		if scheduledPost.Status != ScheduledPostStatusPending || scheduledPost.NextAttemptAt.After(scheduler.clock.Now()) {
+			continue
+		}
+
+		wait := scheduler.spacingWait()
+		if wait > 0 {
+			select {
+			case <-stop:
+				return nil
+			case <-scheduler.clock.After(wait):
+			}
+		}
+
+		e := scheduler.publish(scheduledPost.Id)
+		if e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// spacingWait returns how long to wait before the next publish call
+func (scheduler *Scheduler) spacingWait() time.Duration {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	if scheduler.lastPublish.IsZero() {
+		return 0
+	}
+
+	return scheduler.lastPublish.Add(scheduler.publishSpacing).Sub(scheduler.clock.Now())
+}
+
+func (scheduler *Scheduler) publish(id string) *errortools.Error {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	// re-read, the post might have been canceled or rescheduled in the meantime
+	scheduledPost, e := scheduler.store.GetScheduledPost(id)
+	if e != nil {
+		return e
+	}
+	if scheduledPost == nil || scheduledPost.Status != ScheduledPostStatusPending || scheduledPost.NextAttemptAt.After(scheduler.clock.Now()) {
+		return nil
+	}
+
+	// persist the in-flight state first, so a post whose outcome fails to be saved is never published again
+	scheduledPost.Status = ScheduledPostStatusPublishing
+	scheduledPost.Attempts++
+	e = scheduler.store.SaveScheduledPost(scheduledPost)
+	if e != nil {
+		return e
+	}
+
+	scheduler.lastPublish = scheduler.clock.Now()
+
+	postUrn, e := scheduler.publisher.CreatePost(&scheduledPost.Post)
+	if e == nil {
+		now := scheduler.clock.Now()
+		scheduledPost.Status = ScheduledPostStatusPublished
+		scheduledPost.PostUrn = postUrn
+		scheduledPost.PublishedAt = &now
+		scheduledPost.LastError = ""
+	} else {
+		scheduledPost.LastError = e.Message()
+		if isNoResponseError(e) {
+			// LinkedIn might have created the post, retrying could publish it twice
+			scheduledPost.Status = ScheduledPostStatusNeedsReview
+		} else if isTransientError(e) && scheduledPost.Attempts < scheduler.maxAttempts {
+			backoff := scheduler.retryBackoff << (scheduledPost.Attempts - 1)
+			scheduledPost.Status = ScheduledPostStatusPending
+			scheduledPost.NextAttemptAt = scheduler.clock.Now().Add(backoff)
+		} else {
+			scheduledPost.Status = ScheduledPostStatusFailed
+		}
+	}
+
+	return scheduler.store.SaveScheduledPost(scheduledPost)
+}
+
+// isTransientError reports whether LinkedIn responded with a status that might succeed when retried
+func isTransientError(e *errortools.Error) bool {
+	if e == nil || e.Response() == nil {
+		return false
+	}
+
+	return e.Response().StatusCode == http.StatusTooManyRequests || e.Response().StatusCode >= http.StatusInternalServerError
+}
+
+// isNoResponseError reports whether a request was sent but no response was received, e.g. on a timeout,
+// the request might have been processed
+func isNoResponseError(e *errortools.Error) bool {
+	return e != nil && e.Response() == nil && e.Request() != nil
+}
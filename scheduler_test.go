@@ -0,0 +1,246 @@
+package linkedin
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	errortools "github.com/leapforce-libraries/go_errortools"
+)
+
+// fakeClock only advances when After is called, which fires immediately
+type fakeClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func (clock *fakeClock) Now() time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	return clock.now
+}
+
+func (clock *fakeClock) After(d time.Duration) <-chan time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	clock.now = clock.now.Add(d)
+	c := make(chan time.Time, 1)
+	c <- clock.now
+
+	return c
+}
+
+func (clock *fakeClock) Advance(d time.Duration) {
+	clock.After(d)
+}
+
+type fakePublisher struct {
+	clock  *fakeClock
+	calls  []time.Time
+	errors []*errortools.Error // returned by consecutive calls, nil or missing means success
+}
+
+func (publisher *fakePublisher) CreatePost(post *Post) (string, *errortools.Error) {
+	publisher.calls = append(publisher.calls, publisher.clock.Now())
+
+	i := len(publisher.calls) - 1
+	if i < len(publisher.errors) && publisher.errors[i] != nil {
+		return "", publisher.errors[i]
+	}
+
+	return "urn:li:share:1", nil
+}
+
+// failingStore fails the save with index failAt (0 based)
+type failingStore struct {
+	*MemoryScheduledPostStore
+	saves  int
+	failAt int
+}
+
+func (store *failingStore) SaveScheduledPost(scheduledPost *ScheduledPost) *errortools.Error {
+	store.saves++
+	if store.saves-1 == store.failAt {
+		return errortools.ErrorMessage("store unavailable")
+	}
+
+	return store.MemoryScheduledPostStore.SaveScheduledPost(scheduledPost)
+}
+
+func responseError(statusCode int) *errortools.Error {
+	e := errortools.ErrorMessagef("status %v", statusCode)
+	e.SetRequest(&http.Request{})
+	e.SetResponse(&http.Response{StatusCode: statusCode})
+
+	return e
+}
+
+func noResponseError() *errortools.Error {
+	e := errortools.ErrorMessage("timeout")
+	e.SetRequest(&http.Request{})
+
+	return e
+}
+
+func newTestScheduler(t *testing.T, store ScheduledPostStore, publisher *fakePublisher) *Scheduler {
+	spacing := 10 * time.Second
+	backoff := time.Minute
+	maxAttempts := 3
+
+	scheduler, e := NewScheduler(&SchedulerConfig{
+		Publisher:      publisher,
+		Store:          store,
+		Clock:          publisher.clock,
+		PublishSpacing: &spacing,
+		RetryBackoff:   &backoff,
+		MaxAttempts:    &maxAttempts,
+	})
+	if e != nil {
+		t.Fatal(e.Message())
+	}
+
+	return scheduler
+}
+
+func getScheduledPost(t *testing.T, scheduler *Scheduler, id string) *ScheduledPost {
+	scheduledPost, e := scheduler.store.GetScheduledPost(id)
+	if e != nil {
+		t.Fatal(e.Message())
+	}
+
+	return scheduledPost
+}
+
+func TestSchedulerPublishesDuePostsWithSpacing(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	publisher := &fakePublisher{clock: clock}
+	scheduler := newTestScheduler(t, NewMemoryScheduledPostStore(), publisher)
+
+	first, _ := scheduler.Schedule(&Post{}, clock.Now().Add(-time.Minute))
+	second, _ := scheduler.Schedule(&Post{}, clock.Now())
+	future, _ := scheduler.Schedule(&Post{}, clock.Now().Add(time.Hour))
+
+	e := scheduler.PublishDue(nil)
+	if e != nil {
+		t.Fatal(e.Message())
+	}
+
+	if len(publisher.calls) != 2 {
+		t.Fatalf("expected 2 publish calls, got %v", len(publisher.calls))
+	}
+	if publisher.calls[1].Sub(publisher.calls[0]) < 10*time.Second {
+		t.Errorf("publish calls not spaced: %v", publisher.calls[1].Sub(publisher.calls[0]))
+	}
+	for _, id := range []string{first.Id, second.Id} {
+		if status := getScheduledPost(t, scheduler, id).Status; status != ScheduledPostStatusPublished {
+			t.Errorf("expected %s to be published, got %s", id, status)
+		}
+	}
+	if status := getScheduledPost(t, scheduler, future.Id).Status; status != ScheduledPostStatusPending {
+		t.Errorf("expected future post to be pending, got %s", status)
+	}
+}
+
+func TestSchedulerRetriesTransientErrorsWithBackoff(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	publisher := &fakePublisher{clock: clock, errors: []*errortools.Error{responseError(http.StatusServiceUnavailable)}}
+	scheduler := newTestScheduler(t, NewMemoryScheduledPostStore(), publisher)
+
+	scheduledPost, _ := scheduler.Schedule(&Post{}, clock.Now())
+
+	_ = scheduler.PublishDue(nil)
+
+	retried := getScheduledPost(t, scheduler, scheduledPost.Id)
+	if retried.Status != ScheduledPostStatusPending || retried.Attempts != 1 {
+		t.Fatalf("expected pending after 1 attempt, got %s after %v", retried.Status, retried.Attempts)
+	}
+	if !retried.NextAttemptAt.Equal(clock.Now().Add(time.Minute)) {
+		t.Errorf("unexpected next attempt at %v", retried.NextAttemptAt)
+	}
+
+	// not due yet
+	_ = scheduler.PublishDue(nil)
+	if len(publisher.calls) != 1 {
+		t.Fatalf("expected no retry before the backoff, got %v calls", len(publisher.calls))
+	}
+
+	clock.Advance(time.Minute)
+	_ = scheduler.PublishDue(nil)
+
+	if status := getScheduledPost(t, scheduler, scheduledPost.Id).Status; status != ScheduledPostStatusPublished {
+		t.Errorf("expected published after retry, got %s", status)
+	}
+}
+
+func TestSchedulerDoesNotRetryWithoutResponse(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	publisher := &fakePublisher{clock: clock, errors: []*errortools.Error{noResponseError()}}
+	scheduler := newTestScheduler(t, NewMemoryScheduledPostStore(), publisher)
+
+	scheduledPost, _ := scheduler.Schedule(&Post{}, clock.Now())
+
+	_ = scheduler.PublishDue(nil)
+	clock.Advance(time.Hour)
+	_ = scheduler.PublishDue(nil)
+
+	if len(publisher.calls) != 1 {
+		t.Fatalf("expected 1 publish call, got %v", len(publisher.calls))
+	}
+	if status := getScheduledPost(t, scheduler, scheduledPost.Id).Status; status != ScheduledPostStatusNeedsReview {
+		t.Fatalf("expected needs review, got %s", status)
+	}
+
+	e := scheduler.Reschedule(scheduledPost.Id, clock.Now())
+	if e != nil {
+		t.Fatal(e.Message())
+	}
+	_ = scheduler.PublishDue(nil)
+
+	if status := getScheduledPost(t, scheduler, scheduledPost.Id).Status; status != ScheduledPostStatusPublished {
+		t.Errorf("expected published after reschedule, got %s", status)
+	}
+}
+
+func TestSchedulerDoesNotRepublishWhenSavingOutcomeFails(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	publisher := &fakePublisher{clock: clock}
+	// save 0 schedules, save 1 marks the post in-flight, save 2 records the outcome
+	store := &failingStore{MemoryScheduledPostStore: NewMemoryScheduledPostStore(), failAt: 2}
+	scheduler := newTestScheduler(t, store, publisher)
+
+	scheduledPost, _ := scheduler.Schedule(&Post{}, clock.Now())
+
+	e := scheduler.PublishDue(nil)
+	if e == nil {
+		t.Fatal("expected the store error")
+	}
+	_ = scheduler.PublishDue(nil)
+
+	if len(publisher.calls) != 1 {
+		t.Fatalf("expected 1 publish call, got %v", len(publisher.calls))
+	}
+	if status := getScheduledPost(t, scheduler, scheduledPost.Id).Status; status != ScheduledPostStatusPublishing {
+		t.Errorf("expected publishing, got %s", status)
+	}
+}
+
+func TestSchedulerCancel(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	publisher := &fakePublisher{clock: clock}
+	scheduler := newTestScheduler(t, NewMemoryScheduledPostStore(), publisher)
+
+	scheduledPost, _ := scheduler.Schedule(&Post{}, clock.Now())
+
+	e := scheduler.Cancel(scheduledPost.Id)
+	if e != nil {
+		t.Fatal(e.Message())
+	}
+	_ = scheduler.PublishDue(nil)
+
+	if len(publisher.calls) != 0 {
+		t.Errorf("expected canceled post not to be published")
+	}
+}
@@ -0,0 +1,391 @@
+package linkedin
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	errortools "github.com/leapforce-libraries/go_errortools"
+)
+
+// SyncState is the state remembered between two sync runs for a single account and entity type,
+// HighWaterMark is the latest lastModified seen and limits the posts fetched by SyncPosts
+type SyncState struct {
+	HighWaterMark int64            `json:"highWaterMark"`
+	Entities      map[string]int64 `json:"entities"` // urn → lastModified
+}
+
+// SyncStateStore persists SyncStates, GetSyncState returns nil if no state exists for key yet
+type SyncStateStore interface {
+	GetSyncState(key string) (*SyncState, *errortools.Error)
+	SaveSyncState(key string, state *SyncState) *errortools.Error
+}
+
+// MemorySyncStateStore keeps sync states in memory
+type MemorySyncStateStore struct {
+	mutex  sync.Mutex
+	states map[string]SyncState
+}
+
+func NewMemorySyncStateStore() *MemorySyncStateStore {
+	return &MemorySyncStateStore{
+		states: make(map[string]SyncState),
+	}
+}
+
+func (store *MemorySyncStateStore) GetSyncState(key string) (*SyncState, *errortools.Error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	state, ok := store.states[key]
+	if !ok {
+		return nil, nil
+	}
+
+	return copySyncState(&state), nil
+}
+
+func (store *MemorySyncStateStore) SaveSyncState(key string, state *SyncState) *errortools.Error {
+	if state == nil {
+		return errortools.ErrorMessage("SyncState pointer is nil")
+	}
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.states[key] = *copySyncState(state)
+
+	return nil
+}
+
+func copySyncState(state *SyncState) *SyncState {
+	var s = SyncState{
+		HighWaterMark: state.HighWaterMark,
+		Entities:      make(map[string]int64, len(state.Entities)),
+	}
+	for urn, lastModified := range state.Entities {
+		s.Entities[urn] = lastModified
+	}
+
+	return &s
+}
+
+type SyncChangeType string
+
+const (
+	SyncChangeTypeCreated SyncChangeType = "CREATED"
+	SyncChangeTypeUpdated SyncChangeType = "UPDATED"
+	SyncChangeTypeRemoved SyncChangeType = "REMOVED"
+)
+
+// SyncChange describes a single entity that changed since the previous run, for removed entities only Urn is set
+type SyncChange struct {
+	Type            SyncChangeType   `json:"type"`
+	Urn             string           `json:"urn"`
+	LastModified    int64            `json:"lastModified,omitempty"`
+	AdCampaign      *AdCampaign      `json:"adCampaign,omitempty"`
+	AdCampaignGroup *AdCampaignGroup `json:"adCampaignGroup,omitempty"`
+	AdCreative      *AdCreative      `json:"adCreative,omitempty"`
+	Post            *Post            `json:"post,omitempty"`
+	// LeadFormResponse is set by SyncNewLeads
+	LeadFormResponse *LeadFormResponse `json:"leadFormResponse,omitempty"`
+}
+
+// SyncResult holds the changes of a run, call Commit once the changes have been processed
+// so the next run only returns changes after this one
+type SyncResult struct {
+	Changes []SyncChange
+	key     string
+	state   *SyncState
+	store   SyncStateStore
+}
+
+func (result *SyncResult) HighWaterMark() int64 {
+	return result.state.HighWaterMark
+}
+
+func (result *SyncResult) Commit() *errortools.Error {
+	return result.store.SaveSyncState(result.key, result.state)
+}
+
+type SyncerConfig struct {
+	Service    *Service
+	StateStore SyncStateStore
+}
+
+// Syncer emits ad entities and posts that were created, changed or removed since the previous run, and new leads
+type Syncer struct {
+	service    *Service
+	stateStore SyncStateStore
+}
+
+func NewSyncer(config *SyncerConfig) (*Syncer, *errortools.Error) {
+	if config == nil {
+		return nil, errortools.ErrorMessage("SyncerConfig must not be a nil pointer")
+	}
+	if config.Service == nil {
+		return nil, errortools.ErrorMessage("Service must not be nil")
+	}
+	if config.StateStore == nil {
+		return nil, errortools.ErrorMessage("StateStore must not be nil")
+	}
+
+	return &Syncer{
+		service:    config.Service,
+		stateStore: config.StateStore,
+	}, nil
+}
+
+const syncPageSize uint = 100
+
+func (syncer *Syncer) SyncAdCampaigns(accountId int64) (*SyncResult, *errortools.Error) {
+	pageSize := syncPageSize
+	adCampaigns, e := syncer.service.SearchAdCampaigns(&SearchAdCampaignsConfig{
+		Account:  accountId,
+		PageSize: &pageSize,
+	})
+	if e != nil {
+		return nil, e
+	}
+
+	var changes []SyncChange
+	for i := range *adCampaigns {
+		adCampaign := &(*adCampaigns)[i]
+		changes = append(changes, SyncChange{
+			Urn:          fmt.Sprintf("%s%v", CampaignUrnPrefix, adCampaign.Id),
+			LastModified: adCampaign.ChangeAuditStamps.LastModified.Time,
+			AdCampaign:   adCampaign,
+		})
+	}
+
+	return syncer.diff(fmt.Sprintf("adCampaigns:%v", accountId), changes)
+}
+
+func (syncer *Syncer) SyncAdCampaignGroups(accountId int64) (*SyncResult, *errortools.Error) {
+	pageSize := syncPageSize
+	adCampaignGroups, e := syncer.service.SearchAdCampaignGroups(&SearchAdCampaignGroupsConfig{
+		Account:  accountId,
+		PageSize: &pageSize,
+	})
+	if e != nil {
+		return nil, e
+	}
+
+	var changes []SyncChange
+	for i := range *adCampaignGroups {
+		adCampaignGroup := &(*adCampaignGroups)[i]
+		changes = append(changes, SyncChange{
+			Urn:             fmt.Sprintf("%s%v", CampaignGroupUrnPrefix, adCampaignGroup.Id),
+			LastModified:    adCampaignGroup.ChangeAuditStamps.LastModified.Time,
+			AdCampaignGroup: adCampaignGroup,
+		})
+	}
+
+	return syncer.diff(fmt.Sprintf("adCampaignGroups:%v", accountId), changes)
+}
+
+func (syncer *Syncer) SyncAdCreatives(accountId int64) (*SyncResult, *errortools.Error) {
+	pageSize := syncPageSize
+	adCreatives, e := syncer.service.SearchAdCreatives(&SearchAdCreativesConfig{
+		Account:  accountId,
+		PageSize: &pageSize,
+	})
+	if e != nil {
+		return nil, e
+	}
+
+	var changes []SyncChange
+	for i := range *adCreatives {
+		adCreative := &(*adCreatives)[i]
+		if adCreative.Id == nil {
+			continue
+		}
+		var lastModified int64
+		if adCreative.LastModifiedAt != nil {
+			lastModified = *adCreative.LastModifiedAt
+		}
+		changes = append(changes, SyncChange{
+			Urn:          *adCreative.Id,
+			LastModified: lastModified,
+			AdCreative:   adCreative,
+		})
+	}
+
+	return syncer.diff(fmt.Sprintf("adCreatives:%v", accountId), changes)
+}
+
+// SyncPosts returns the posts created or updated since the high-water mark of the previous run,
+// only those posts are fetched so removed posts are not detected, use SyncRemovedPosts for that
+func (syncer *Syncer) SyncPosts(organizationId int64) (*SyncResult, *errortools.Error) {
+	key := fmt.Sprintf("posts:%v", organizationId)
+
+	previous, e := syncer.stateStore.GetSyncState(key)
+	if e != nil {
+		return nil, e
+	}
+
+	config := PostsByOwnerConfig{
+		OrganizationId: organizationId,
+	}
+	if previous != nil && previous.HighWaterMark > 0 {
+		// posts modified at the mark itself are fetched again, diff skips them if unchanged
+		config.LastModifiedStartDateUnix = &previous.HighWaterMark
+	}
+
+	posts, e := syncer.service.PostsByOwner(&config)
+	if e != nil {
+		return nil, e
+	}
+
+	var changes []SyncChange
+	for i := range *posts {
+		post := &(*posts)[i]
+		changes = append(changes, SyncChange{
+			Urn:          post.Id,
+			LastModified: post.LastModifiedAt,
+			Post:         post,
+		})
+	}
+
+	return syncer.merge(key, previous, changes)
+}
+
+// SyncRemovedPosts lists all posts and returns the posts seen by SyncPosts that no longer exist,
+// Commit the result of SyncPosts before calling it as both share the same state
+func (syncer *Syncer) SyncRemovedPosts(organizationId int64) (*SyncResult, *errortools.Error) {
+	key := fmt.Sprintf("posts:%v", organizationId)
+
+	previous, e := syncer.stateStore.GetSyncState(key)
+	if e != nil {
+		return nil, e
+	}
+	if previous == nil {
+		previous = &SyncState{Entities: make(map[string]int64)}
+	}
+
+	posts, e := syncer.service.PostsByOwner(&PostsByOwnerConfig{
+		OrganizationId: organizationId,
+	})
+	if e != nil {
+		return nil, e
+	}
+
+	var existing = make(map[string]bool, len(*posts))
+	for _, post := range *posts {
+		existing[post.Id] = true
+	}
+
+	// posts not seen by SyncPosts yet are left to it, so their creation is not missed
+	var result = SyncResult{
+		key:   key,
+		state: copySyncState(previous),
+		store: syncer.stateStore,
+	}
+	result.Changes = removedChanges(previous, existing)
+	for _, change := range result.Changes {
+		delete(result.state.Entities, change.Urn)
+	}
+
+	return &result, nil
+}
+
+// merge adds the fetched entities to the stored state and returns the created and updated ones,
+// entities that were not fetched are kept
+func (syncer *Syncer) merge(key string, previous *SyncState, current []SyncChange) (*SyncResult, *errortools.Error) {
+	if previous == nil {
+		previous = &SyncState{Entities: make(map[string]int64)}
+	}
+
+	var result = SyncResult{
+		key:   key,
+		state: copySyncState(previous),
+		store: syncer.stateStore,
+	}
+
+	for _, change := range current {
+		if change.LastModified > result.state.HighWaterMark {
+			result.state.HighWaterMark = change.LastModified
+		}
+
+		lastModified, ok := previous.Entities[change.Urn]
+		if !ok {
+			change.Type = SyncChangeTypeCreated
+		} else if change.LastModified > lastModified {
+			change.Type = SyncChangeTypeUpdated
+		} else {
+			continue
+		}
+
+		result.state.Entities[change.Urn] = change.LastModified
+		result.Changes = append(result.Changes, change)
+	}
+
+	return &result, nil
+}
+
+// diff compares the current entities with the stored state and returns the created, updated and removed ones
+func (syncer *Syncer) diff(key string, current []SyncChange) (*SyncResult, *errortools.Error) {
+	previous, e := syncer.stateStore.GetSyncState(key)
+	if e != nil {
+		return nil, e
+	}
+	if previous == nil {
+		previous = &SyncState{Entities: make(map[string]int64)}
+	}
+
+	var result = SyncResult{
+		key: key,
+		state: &SyncState{
+			HighWaterMark: previous.HighWaterMark,
+			Entities:      make(map[string]int64, len(current)),
+		},
+		store: syncer.stateStore,
+	}
+
+	for _, change := range current {
+		result.state.Entities[change.Urn] = change.LastModified
+		if change.LastModified > result.state.HighWaterMark {
+			result.state.HighWaterMark = change.LastModified
+		}
+
+		lastModified, ok := previous.Entities[change.Urn]
+		if !ok {
+			change.Type = SyncChangeTypeCreated
+		} else if change.LastModified > lastModified {
+			change.Type = SyncChangeTypeUpdated
+		} else {
+			continue
+		}
+
+		result.Changes = append(result.Changes, change)
+	}
+
+	var existing = make(map[string]bool, len(result.state.Entities))
+	for urn := range result.state.Entities {
+		existing[urn] = true
+	}
+	result.Changes = append(result.Changes, removedChanges(previous, existing)...)
+
+	return &result, nil
+}
+
+// removedChanges returns a REMOVED change for every entity in previous that does not exist anymore, sorted by urn
+func removedChanges(previous *SyncState, existing map[string]bool) []SyncChange {
+	var removed []string
+	for urn := range previous.Entities {
+		if !existing[urn] {
+			removed = append(removed, urn)
+		}
+	}
+	sort.Strings(removed)
+
+	var changes []SyncChange
+	for _, urn := range removed {
+		changes = append(changes, SyncChange{
+			Type: SyncChangeTypeRemoved,
+			Urn:  urn,
+		})
+	}
+
+	return changes
+}
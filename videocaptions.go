@@ -0,0 +1,188 @@
+package linkedin
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	errortools "github.com/leapforce-libraries/go_errortools"
+	go_http "github.com/leapforce-libraries/go_http"
+)
+
+const (
+	MaxVideoCaptionsSize          int64   = 1 << 20 // bytes
+	MaxVideoThumbnailSize         int64   = 2 << 20 // bytes
+	MinVideoThumbnailDimension    int     = 360     // pixels, width and height
+	MaxVideoThumbnailDimension    int     = 4096    // pixels, width and height
+	videoThumbnailAspectTolerance float64 = 0.02
+)
+
+var srtTimingRegexp = regexp.MustCompile(`^(\d{2,}):([0-5]\d):([0-5]\d),(\d{3}) --> (\d{2,}):([0-5]\d):([0-5]\d),(\d{3})(\s.*)?$`)
+
+func srtMillis(match []string) int64 {
+	var ms int64
+	for i, factor := range []int64{3600000, 60000, 1000, 1} {
+		v, _ := strconv.ParseInt(match[i], 10, 64)
+		ms += v * factor
+	}
+
+	return ms
+}
+
+// ValidateSrt checks that b is a well-formed SubRip (SRT) caption file
+func ValidateSrt(b []byte) *errortools.Error {
+	b = bytes.TrimPrefix(b, []byte("\xef\xbb\xbf"))
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	// a caption file is at most MaxVideoCaptionsSize, so no line can exceed it
+	scanner.Buffer(make([]byte, 0, 64*1024), int(MaxVideoCaptionsSize)+1)
+
+	var lineNumber int
+	var cues int
+	var lastIndex int64
+	var lastStart int64
+
+	// states: 0 = expecting index, 1 = expecting timing, 2 = expecting text, 3 = text or blank line
+	state := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		switch state {
+		case 0:
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			index, err := strconv.ParseInt(strings.TrimSpace(line), 10, 64)
+			if err != nil || index <= lastIndex {
+				return errortools.ErrorMessagef("Invalid SRT: line %v should be a cue number greater than %v", lineNumber, lastIndex)
+			}
+			lastIndex = index
+			state = 1
+		case 1:
+			match := srtTimingRegexp.FindStringSubmatch(strings.TrimSpace(line))
+			if match == nil {
+				return errortools.ErrorMessagef("Invalid SRT: line %v should be a timing line 'hh:mm:ss,mmm --> hh:mm:ss,mmm'", lineNumber)
+			}
+			start := srtMillis(match[1:5])
+			end := srtMillis(match[5:9])
+			if end < start {
+				return errortools.ErrorMessagef("Invalid SRT: cue on line %v ends before it starts", lineNumber)
+			}
+			if start < lastStart {
+				return errortools.ErrorMessagef("Invalid SRT: cue on line %v starts before the previous cue", lineNumber)
+			}
+			lastStart = start
+			state = 2
+		case 2:
+			if strings.TrimSpace(line) == "" {
+				return errortools.ErrorMessagef("Invalid SRT: cue %v has no text", lastIndex)
+			}
+			cues++
+			state = 3
+		case 3:
+			if strings.TrimSpace(line) == "" {
+				state = 0
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		if err == bufio.ErrTooLong {
+			return errortools.ErrorMessagef("Invalid SRT: line %v exceeds %v bytes", lineNumber+1, MaxVideoCaptionsSize)
+		}
+		return errortools.ErrorMessage(err)
+	}
+
+	if state == 1 || state == 2 {
+		return errortools.ErrorMessagef("Invalid SRT: cue %v is incomplete", lastIndex)
+	}
+	if cues == 0 {
+		return errortools.ErrorMessage("Invalid SRT: no cues found")
+	}
+
+	return nil
+}
+
+// UploadVideoCaptions uploads an SRT caption file to the captionsUploadUrl returned by InitializeUploadVideo,
+// size being the number of bytes to read or -1 if unknown
+func (service *Service) UploadVideoCaptions(captionsUploadUrl string, reader io.Reader, size int64) *errortools.Error {
+	if service == nil {
+		return errortools.ErrorMessage("Service pointer is nil")
+	}
+	if captionsUploadUrl == "" {
+		return errortools.ErrorMessage("No captions upload url, initialize the upload with UploadCaptions set to true")
+	}
+
+	b, e := readMedia(reader, size, MaxVideoCaptionsSize)
+	if e != nil {
+		return e
+	}
+
+	e = ValidateSrt(b)
+	if e != nil {
+		return e
+	}
+
+	return service.uploadVideoMedia(captionsUploadUrl, "application/octet-stream", b)
+}
+
+// UploadVideoThumbnail uploads a JPEG or PNG thumbnail to the thumbnailUploadUrl returned by InitializeUploadVideo,
+// size being the number of bytes to read or -1 if unknown. The thumbnail must be at most MaxVideoThumbnailSize bytes
+// with sides between MinVideoThumbnailDimension and MaxVideoThumbnailDimension, if aspectRatio is set it must match it.
+func (service *Service) UploadVideoThumbnail(thumbnailUploadUrl string, reader io.Reader, size int64, aspectRatio *AspectRatio) *errortools.Error {
+	if service == nil {
+		return errortools.ErrorMessage("Service pointer is nil")
+	}
+	if thumbnailUploadUrl == "" {
+		return errortools.ErrorMessage("No thumbnail upload url, initialize the upload with UploadThumbnail set to true")
+	}
+
+	b, e := readMedia(reader, size, MaxVideoThumbnailSize)
+	if e != nil {
+		return e
+	}
+
+	contentType, config, e := validateImage(b)
+	if e != nil {
+		return e
+	}
+	if contentType == "image/gif" {
+		return errortools.ErrorMessage("Video thumbnail must be a JPEG or PNG image")
+	}
+	if config.Width < MinVideoThumbnailDimension || config.Height < MinVideoThumbnailDimension {
+		return errortools.ErrorMessagef("Video thumbnail of %vx%v is smaller than the minimum of %vx%v", config.Width, config.Height, MinVideoThumbnailDimension, MinVideoThumbnailDimension)
+	}
+	if config.Width > MaxVideoThumbnailDimension || config.Height > MaxVideoThumbnailDimension {
+		return errortools.ErrorMessagef("Video thumbnail of %vx%v exceeds the maximum of %vx%v", config.Width, config.Height, MaxVideoThumbnailDimension, MaxVideoThumbnailDimension)
+	}
+	if aspectRatio != nil && aspectRatio.WidthAspect > 0 && aspectRatio.HeightAspect > 0 {
+		expected := aspectRatio.WidthAspect / aspectRatio.HeightAspect
+		actual := float64(config.Width) / float64(config.Height)
+		if math.Abs(actual-expected)/expected > videoThumbnailAspectTolerance {
+			return errortools.ErrorMessagef("Video thumbnail of %vx%v does not match the aspect ratio %v:%v of the video", config.Width, config.Height, aspectRatio.WidthAspect, aspectRatio.HeightAspect)
+		}
+	}
+
+	return service.uploadVideoMedia(thumbnailUploadUrl, contentType, b)
+}
+
+func (service *Service) uploadVideoMedia(uploadUrl string, contentType string, b []byte) *errortools.Error {
+	var header = http.Header{}
+	header.Set("Content-Type", contentType)
+
+	requestConfig := go_http.RequestConfig{
+		Method:            http.MethodPut,
+		Url:               uploadUrl,
+		BodyRaw:           &b,
+		NonDefaultHeaders: &header,
+	}
+	_, _, e := service.oAuth2Service.HttpRequest(&requestConfig)
+
+	return e
+}
@@ -0,0 +1,391 @@
+package linkedin
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	errortools "github.com/leapforce-libraries/go_errortools"
+	go_http "github.com/leapforce-libraries/go_http"
+)
+
+const (
+	defaultVideoUploadParallelism  int           = 4
+	defaultVideoUploadMaxAttempts  int           = 3
+	defaultVideoUploadRetryBackoff time.Duration = 2 * time.Second
+)
+
+// VideoUploadSession holds the state of a multipart video upload, persisting it allows a crashed upload to resume
+type VideoUploadSession struct {
+	Video               string                             `json:"video"`
+	UploadToken         string                             `json:"uploadToken"`
+	UploadUrlsExpiresAt int64                              `json:"uploadUrlsExpiresAt"`
+	FileSizeBytes       int64                              `json:"fileSizeBytes"`
+	UploadInstructions  []InitializeUploadVideoInstruction `json:"uploadInstructions"`
+	ETags               []string                           `json:"eTags"` // per upload instruction, empty if the part has not been uploaded yet
+	CaptionsUploadUrl   string                             `json:"captionsUploadUrl,omitempty"`
+	ThumbnailUploadUrl  string                             `json:"thumbnailUploadUrl,omitempty"`
+	Finalized           bool                               `json:"finalized"`
+}
+
+func copyVideoUploadSession(session *VideoUploadSession) *VideoUploadSession {
+	_session := *session
+	_session.UploadInstructions = append([]InitializeUploadVideoInstruction{}, session.UploadInstructions...)
+	_session.ETags = append([]string{}, session.ETags...)
+
+	return &_session
+}
+
+// VideoUploadSessionStore persists VideoUploadSessions, GetVideoUploadSession returns nil if no session exists for key
+type VideoUploadSessionStore interface {
+	GetVideoUploadSession(key string) (*VideoUploadSession, *errortools.Error)
+	SaveVideoUploadSession(key string, session *VideoUploadSession) *errortools.Error
+	DeleteVideoUploadSession(key string) *errortools.Error
+}
+
+// MemoryVideoUploadSessionStore keeps upload sessions in memory
+type MemoryVideoUploadSessionStore struct {
+	mutex    sync.Mutex
+	sessions map[string]VideoUploadSession
+}
+
+func NewMemoryVideoUploadSessionStore() *MemoryVideoUploadSessionStore {
+	return &MemoryVideoUploadSessionStore{
+		sessions: make(map[string]VideoUploadSession),
+	}
+}
+
+func (store *MemoryVideoUploadSessionStore) GetVideoUploadSession(key string) (*VideoUploadSession, *errortools.Error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	session, ok := store.sessions[key]
+	if !ok {
+		return nil, nil
+	}
+
+	return copyVideoUploadSession(&session), nil
+}
+
+func (store *MemoryVideoUploadSessionStore) SaveVideoUploadSession(key string, session *VideoUploadSession) *errortools.Error {
+	if session == nil {
+		return errortools.ErrorMessage("VideoUploadSession pointer is nil")
+	}
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.sessions[key] = *copyVideoUploadSession(session)
+
+	return nil
+}
+
+func (store *MemoryVideoUploadSessionStore) DeleteVideoUploadSession(key string) *errortools.Error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	delete(store.sessions, key)
+
+	return nil
+}
+
+type VideoUploadProgress struct {
+	PartsUploaded int
+	PartsTotal    int
+	BytesUploaded int64
+	BytesTotal    int64
+}
+
+type StreamUploadVideoConfig struct {
+	Owner           string
+	Reader          io.ReaderAt
+	FileSizeBytes   int64
+	UploadCaptions  *bool
+	UploadThumbnail *bool
+	// Captions (SRT) and Thumbnail are optional and uploaded before the upload is finalized,
+	// setting them implies UploadCaptions and UploadThumbnail
+	Captions  io.Reader
+	Thumbnail io.Reader
+	// AspectRatio of the video, when set the Thumbnail must match it
+	AspectRatio *AspectRatio
+	// SessionStore and SessionKey are optional, when set the upload resumes from a previously persisted session
+	SessionStore VideoUploadSessionStore
+	SessionKey   string
+	Parallelism  *int           // number of parts uploaded concurrently, default 4
+	MaxAttempts  *int           // attempts per part, default 3
+	RetryBackoff *time.Duration // doubled after each failed attempt, default 2 seconds
+	// Progress is called after each uploaded part, calls are serialized
+	Progress func(progress VideoUploadProgress)
+}
+
+// StreamUploadVideo uploads a video in parts read from an io.ReaderAt and finalizes the upload,
+// parts are uploaded in parallel and retried on transient errors
+func (service *Service) StreamUploadVideo(config *StreamUploadVideoConfig) (*VideoUploadSession, *errortools.Error) {
+	if service == nil {
+		return nil, errortools.ErrorMessage("Service pointer is nil")
+	}
+	if config == nil {
+		return nil, errortools.ErrorMessage("StreamUploadVideoConfig must not be a nil pointer")
+	}
+	if config.Reader == nil {
+		return nil, errortools.ErrorMessage("Reader must not be nil")
+	}
+	if config.FileSizeBytes <= 0 {
+		return nil, errortools.ErrorMessage("FileSizeBytes must be positive")
+	}
+
+	session, e := service.videoUploadSession(config)
+	if e != nil {
+		return nil, e
+	}
+
+	if !session.Finalized {
+		e = service.uploadVideoParts(config, session)
+		if e != nil {
+			return nil, e
+		}
+
+		if config.Captions != nil {
+			e = service.UploadVideoCaptions(session.CaptionsUploadUrl, config.Captions, -1)
+			if e != nil {
+				return nil, e
+			}
+		}
+		if config.Thumbnail != nil {
+			e = service.UploadVideoThumbnail(session.ThumbnailUploadUrl, config.Thumbnail, -1, config.AspectRatio)
+			if e != nil {
+				return nil, e
+			}
+		}
+
+		e = service.FinalizeUploadVideo(&FinalizeUploadVideoRequest{
+			Video:           session.Video,
+			UploadToken:     session.UploadToken,
+			UploadedPartIds: session.ETags,
+		})
+		if e != nil {
+			return nil, e
+		}
+		session.Finalized = true
+
+		if config.SessionStore != nil {
+			e = config.SessionStore.SaveVideoUploadSession(config.SessionKey, session)
+			if e != nil {
+				return nil, e
+			}
+		}
+	}
+
+	if config.SessionStore != nil {
+		e = config.SessionStore.DeleteVideoUploadSession(config.SessionKey)
+		if e != nil {
+			return nil, e
+		}
+	}
+
+	return session, nil
+}
+
+// videoUploadSession returns the persisted session if it is still usable, otherwise it initializes a new upload
+func (service *Service) videoUploadSession(config *StreamUploadVideoConfig) (*VideoUploadSession, *errortools.Error) {
+	if config.SessionStore != nil {
+		session, e := config.SessionStore.GetVideoUploadSession(config.SessionKey)
+		if e != nil {
+			return nil, e
+		}
+		if session != nil &&
+			session.FileSizeBytes == config.FileSizeBytes &&
+			len(session.ETags) == len(session.UploadInstructions) &&
+			(session.Finalized || time.Now().UnixMilli() < session.UploadUrlsExpiresAt) {
+			return session, nil
+		}
+	}
+
+	fileSizeBytes := config.FileSizeBytes
+	uploadCaptions := config.UploadCaptions
+	uploadThumbnail := config.UploadThumbnail
+	_true := true
+	if config.Captions != nil {
+		uploadCaptions = &_true
+	}
+	if config.Thumbnail != nil {
+		uploadThumbnail = &_true
+	}
+
+	response, e := service.InitializeUploadVideo(&InitializeUploadVideoRequest{
+		Owner:           config.Owner,
+		FileSizeBytes:   &fileSizeBytes,
+		UploadCaptions:  uploadCaptions,
+		UploadThumbnail: uploadThumbnail,
+	})
+	if e != nil {
+		return nil, e
+	}
+
+	session := VideoUploadSession{
+		Video:               response.Value.Video,
+		UploadToken:         response.Value.UploadToken,
+		UploadUrlsExpiresAt: response.Value.UploadUrlsExpiresAt,
+		FileSizeBytes:       fileSizeBytes,
+		UploadInstructions:  response.Value.UploadInstructions,
+		ETags:               make([]string, len(response.Value.UploadInstructions)),
+		CaptionsUploadUrl:   response.Value.CaptionsUploadUrl,
+		ThumbnailUploadUrl:  response.Value.ThumbnailUploadUrl,
+	}
+
+	if config.SessionStore != nil {
+		e = config.SessionStore.SaveVideoUploadSession(config.SessionKey, &session)
+		if e != nil {
+			return nil, e
+		}
+	}
+
+	return &session, nil
+}
+
+func (service *Service) uploadVideoParts(config *StreamUploadVideoConfig, session *VideoUploadSession) *errortools.Error {
+	parallelism := defaultVideoUploadParallelism
+	if config.Parallelism != nil && *config.Parallelism > 0 {
+		parallelism = *config.Parallelism
+	}
+
+	progress := VideoUploadProgress{
+		PartsTotal: len(session.UploadInstructions),
+		BytesTotal: session.FileSizeBytes,
+	}
+	for i, etag := range session.ETags {
+		if etag != "" {
+			progress.PartsUploaded++
+			progress.BytesUploaded += session.UploadInstructions[i].LastByte - session.UploadInstructions[i].FirstByte + 1
+		}
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	var firstError *errortools.Error
+	semaphore := make(chan struct{}, parallelism)
+
+	for i := range session.UploadInstructions {
+		if session.ETags[i] != "" {
+			continue
+		}
+
+		mutex.Lock()
+		failed := firstError != nil
+		mutex.Unlock()
+		if failed {
+			break
+		}
+
+		semaphore <- struct{}{}
+		wg.Add(1)
+
+		go func(i int) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			instruction := session.UploadInstructions[i]
+
+			etag, e := service.uploadVideoPartWithRetry(config, &instruction)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if e != nil {
+				if firstError == nil {
+					firstError = e
+				}
+				return
+			}
+
+			session.ETags[i] = etag
+			progress.PartsUploaded++
+			progress.BytesUploaded += instruction.LastByte - instruction.FirstByte + 1
+
+			if config.SessionStore != nil {
+				e = config.SessionStore.SaveVideoUploadSession(config.SessionKey, session)
+				if e != nil && firstError == nil {
+					firstError = e
+					return
+				}
+			}
+			if config.Progress != nil {
+				config.Progress(progress)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	return firstError
+}
+
+func (service *Service) uploadVideoPartWithRetry(config *StreamUploadVideoConfig, instruction *InitializeUploadVideoInstruction) (string, *errortools.Error) {
+	maxAttempts := defaultVideoUploadMaxAttempts
+	if config.MaxAttempts != nil && *config.MaxAttempts > 0 {
+		maxAttempts = *config.MaxAttempts
+	}
+	backoff := defaultVideoUploadRetryBackoff
+	if config.RetryBackoff != nil {
+		backoff = *config.RetryBackoff
+	}
+
+	var e *errortools.Error
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		var etag string
+		etag, e = service.uploadVideoPart(config.Reader, instruction)
+		if e == nil {
+			return etag, nil
+		}
+		// part uploads are idempotent, so they are also retried when no response was received
+		if !(isTransientError(e) || isNoResponseError(e)) || attempt == maxAttempts {
+			break
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	return "", e
+}
+
+// uploadVideoPart reads the byte range of the upload instruction from reader and uploads it, returning the etag
+func (service *Service) uploadVideoPart(reader io.ReaderAt, instruction *InitializeUploadVideoInstruction) (string, *errortools.Error) {
+	if instruction.LastByte < instruction.FirstByte {
+		return "", errortools.ErrorMessagef("Invalid byte range %v-%v", instruction.FirstByte, instruction.LastByte)
+	}
+
+	b := make([]byte, instruction.LastByte-instruction.FirstByte+1)
+	n, err := reader.ReadAt(b, instruction.FirstByte)
+	if n < len(b) {
+		if err == nil || err == io.EOF {
+			return "", errortools.ErrorMessagef("Read %v bytes at offset %v, expected %v", n, instruction.FirstByte, len(b))
+		}
+		return "", errortools.ErrorMessage(err)
+	}
+
+	var header = http.Header{}
+	header.Set("Content-Type", "application/octet-stream")
+
+	requestConfig := go_http.RequestConfig{
+		Method:            http.MethodPut,
+		Url:               instruction.UploadUrl,
+		BodyRaw:           &b,
+		NonDefaultHeaders: &header,
+	}
+	_, resp, e := service.oAuth2Service.HttpRequest(&requestConfig)
+	if e != nil {
+		return "", e
+	}
+
+	etag := resp.Header.Get("etag")
+	if etag == "" {
+		return "", errortools.ErrorMessage("UploadVideo did not return etag header")
+	}
+
+	return etag, nil
+}